@@ -1,8 +1,10 @@
 package retry
 
 import (
+	"fmt"
 	"math"
 	"math/rand/v2"
+	"strconv"
 	"time"
 )
 
@@ -19,74 +21,358 @@ func (f BackoffFunc) Delay(attempt int) time.Duration {
 	return f(attempt)
 }
 
+// checkedBackoff is implemented by the built-in strategies so that a policy
+// can validate them.
+type checkedBackoff interface {
+	Backoff
+	// validate appends a problem for each invalid setting in the chain.
+	validate(problems []string) []string
+	// floor is the smallest delay the backoff can produce.
+	floor() time.Duration
+}
+
 // Constant returns a backoff that always waits the same duration.
 func Constant(d time.Duration) Backoff {
-	return BackoffFunc(func(attempt int) time.Duration {
-		return d
-	})
+	return ConstantBackoff{d: d}
+}
+
+// ConstantBackoff waits the same duration before every retry.
+// It is created by Constant.
+type ConstantBackoff struct {
+	d time.Duration
+}
+
+// Delay implements Backoff.
+func (b ConstantBackoff) Delay(int) time.Duration {
+	return b.d
+}
+
+// Duration returns the delay applied before every retry.
+func (b ConstantBackoff) Duration() time.Duration {
+	return b.d
+}
+
+// String returns the backoff as constant(d).
+func (b ConstantBackoff) String() string {
+	return "constant(" + b.d.String() + ")"
+}
+
+func (b ConstantBackoff) validate(problems []string) []string {
+	if b.d < 0 {
+		problems = append(problems, fmt.Sprintf("constant delay %v is negative", b.d))
+	}
+	return problems
+}
+
+func (b ConstantBackoff) floor() time.Duration {
+	return b.d
 }
 
 // Linear returns a backoff that increases linearly with each attempt.
 // delay = base * attempt
 func Linear(base time.Duration) Backoff {
-	return BackoffFunc(func(attempt int) time.Duration {
-		return base * time.Duration(attempt)
-	})
+	return LinearBackoff{base: base}
+}
+
+// LinearBackoff waits base * attempt before each retry.
+// It is created by Linear.
+type LinearBackoff struct {
+	base time.Duration
+}
+
+// Delay implements Backoff.
+func (b LinearBackoff) Delay(attempt int) time.Duration {
+	return b.base * time.Duration(attempt)
+}
+
+// Base returns the delay before the first retry.
+func (b LinearBackoff) Base() time.Duration {
+	return b.base
+}
+
+// String returns the backoff as linear(base).
+func (b LinearBackoff) String() string {
+	return "linear(" + b.base.String() + ")"
+}
+
+func (b LinearBackoff) validate(problems []string) []string {
+	if b.base < 0 {
+		problems = append(problems, fmt.Sprintf("linear base %v is negative", b.base))
+	}
+	return problems
+}
+
+func (b LinearBackoff) floor() time.Duration {
+	return b.base
 }
 
 // Exponential returns a backoff that doubles with each attempt.
 // delay = base * 2^(attempt-1)
 func Exponential(base time.Duration) Backoff {
-	return BackoffFunc(func(attempt int) time.Duration {
-		if attempt <= 0 {
-			return base
-		}
-		// Prevent overflow
-		if attempt > 62 {
-			return time.Duration(math.MaxInt64)
-		}
-		return base * time.Duration(1<<uint(attempt-1))
-	})
+	return ExponentialBackoff{base: base}
+}
+
+// ExponentialBackoff waits base * 2^(attempt-1) before each retry.
+// It is created by Exponential.
+type ExponentialBackoff struct {
+	base time.Duration
+}
+
+// Delay implements Backoff.
+func (b ExponentialBackoff) Delay(attempt int) time.Duration {
+	if attempt <= 0 {
+		return b.base
+	}
+	// Prevent overflow
+	if attempt > 62 {
+		return time.Duration(math.MaxInt64)
+	}
+	return b.base * time.Duration(1<<uint(attempt-1))
+}
+
+// Base returns the delay before the first retry.
+func (b ExponentialBackoff) Base() time.Duration {
+	return b.base
+}
+
+// String returns the backoff as exponential(base).
+func (b ExponentialBackoff) String() string {
+	return "exponential(" + b.base.String() + ")"
+}
+
+func (b ExponentialBackoff) validate(problems []string) []string {
+	if b.base < 0 {
+		problems = append(problems, fmt.Sprintf("exponential base %v is negative", b.base))
+	}
+	return problems
+}
+
+func (b ExponentialBackoff) floor() time.Duration {
+	return b.base
 }
 
 // WithCap wraps a backoff and caps the delay at a maximum value.
 func WithCap(max time.Duration, b Backoff) Backoff {
-	return BackoffFunc(func(attempt int) time.Duration {
-		d := b.Delay(attempt)
-		if d > max {
-			return max
-		}
-		return d
-	})
+	return CapBackoff{max: max, b: b}
+}
+
+// CapBackoff limits the delay of a wrapped backoff to a maximum.
+// It is created by WithCap.
+type CapBackoff struct {
+	max time.Duration
+	b   Backoff
+}
+
+// Delay implements Backoff.
+func (b CapBackoff) Delay(attempt int) time.Duration {
+	d := b.b.Delay(attempt)
+	if d > b.max {
+		return b.max
+	}
+	return d
+}
+
+// Max returns the largest delay the backoff produces.
+func (b CapBackoff) Max() time.Duration {
+	return b.max
+}
+
+// Unwrap returns the wrapped backoff.
+func (b CapBackoff) Unwrap() Backoff {
+	return b.b
+}
+
+// String returns the backoff as cap(max, wrapped).
+func (b CapBackoff) String() string {
+	return "cap(" + b.max.String() + ", " + backoffString(b.b) + ")"
+}
+
+func (b CapBackoff) validate(problems []string) []string {
+	if b.max < 0 {
+		problems = append(problems, fmt.Sprintf("cap %v is negative", b.max))
+	}
+	return checkBackoff(b.b, problems)
+}
+
+func (b CapBackoff) floor() time.Duration {
+	f, _ := floorOf(b.b)
+	return min(f, b.max)
 }
 
 // WithMin wraps a backoff and ensures the delay is at least a minimum value.
 func WithMin(min time.Duration, b Backoff) Backoff {
-	return BackoffFunc(func(attempt int) time.Duration {
-		d := b.Delay(attempt)
-		if d < min {
-			return min
-		}
-		return d
-	})
+	return MinBackoff{min: min, b: b}
+}
+
+// MinBackoff raises the delay of a wrapped backoff to a minimum.
+// It is created by WithMin.
+type MinBackoff struct {
+	min time.Duration
+	b   Backoff
+}
+
+// Delay implements Backoff.
+func (b MinBackoff) Delay(attempt int) time.Duration {
+	d := b.b.Delay(attempt)
+	if d < b.min {
+		return b.min
+	}
+	return d
+}
+
+// Min returns the smallest delay the backoff produces.
+func (b MinBackoff) Min() time.Duration {
+	return b.min
+}
+
+// Unwrap returns the wrapped backoff.
+func (b MinBackoff) Unwrap() Backoff {
+	return b.b
+}
+
+// String returns the backoff as min(min, wrapped).
+func (b MinBackoff) String() string {
+	return "min(" + b.min.String() + ", " + backoffString(b.b) + ")"
+}
+
+func (b MinBackoff) validate(problems []string) []string {
+	if b.min < 0 {
+		problems = append(problems, fmt.Sprintf("min %v is negative", b.min))
+	}
+	return checkBackoff(b.b, problems)
+}
+
+func (b MinBackoff) floor() time.Duration {
+	f, _ := floorOf(b.b)
+	return max(f, b.min)
 }
 
 // WithJitter wraps a backoff and adds random jitter to the delay.
 // The jitter is a factor between 0 and 1, where 0.2 means ±20%.
 func WithJitter(factor float64, b Backoff) Backoff {
-	return BackoffFunc(func(attempt int) time.Duration {
-		d := b.Delay(attempt)
-		if factor <= 0 {
-			return d
+	return JitterBackoff{factor: factor, b: b}
+}
+
+// JitterBackoff randomizes the delay of a wrapped backoff by ±factor.
+// It is created by WithJitter.
+type JitterBackoff struct {
+	factor float64
+	b      Backoff
+}
+
+// Delay implements Backoff.
+func (b JitterBackoff) Delay(attempt int) time.Duration {
+	d := b.b.Delay(attempt)
+	if b.factor <= 0 {
+		return d
+	}
+	// Calculate jitter range: delay * factor
+	jitterRange := float64(d) * b.factor
+	// Random value between -jitterRange and +jitterRange
+	jitter := (rand.Float64()*2 - 1) * jitterRange
+	result := time.Duration(float64(d) + jitter)
+	if result < 0 {
+		return 0
+	}
+	return result
+}
+
+// Factor returns the jitter factor, where 0.2 means ±20%.
+func (b JitterBackoff) Factor() float64 {
+	return b.factor
+}
+
+// Unwrap returns the wrapped backoff.
+func (b JitterBackoff) Unwrap() Backoff {
+	return b.b
+}
+
+// String returns the backoff as jitter(factor, wrapped).
+func (b JitterBackoff) String() string {
+	return "jitter(" + strconv.FormatFloat(b.factor, 'g', -1, 64) + ", " + backoffString(b.b) + ")"
+}
+
+func (b JitterBackoff) validate(problems []string) []string {
+	if b.factor < 0 || b.factor > 1 || math.IsNaN(b.factor) {
+		problems = append(problems, fmt.Sprintf("jitter factor %v is outside [0, 1]", b.factor))
+	}
+	return checkBackoff(b.b, problems)
+}
+
+func (b JitterBackoff) floor() time.Duration {
+	f, _ := floorOf(b.b)
+	if b.factor <= 0 {
+		return f
+	}
+	return max(time.Duration(float64(f)*(1-b.factor)), 0)
+}
+
+// backoffString describes b using its String method when it has one.
+// Backoffs without one, such as a BackoffFunc, are described as "custom".
+func backoffString(b Backoff) string {
+	switch b := b.(type) {
+	case nil:
+		return "nil"
+	case fmt.Stringer:
+		return b.String()
+	default:
+		return "custom"
+	}
+}
+
+// validateBackoff appends the problems found in b, which may be nil or a
+// custom implementation.
+func validateBackoff(b Backoff, problems []string) []string {
+	if lo, hi, hasLo, hasHi := bounds(b); hasLo && hasHi && hi < lo {
+		problems = append(problems, fmt.Sprintf("cap %v is below min %v", hi, lo))
+	}
+	return checkBackoff(b, problems)
+}
+
+// checkBackoff validates each strategy in b's chain.
+func checkBackoff(b Backoff, problems []string) []string {
+	switch b := b.(type) {
+	case nil:
+		return append(problems, "backoff is nil")
+	case BackoffFunc:
+		if b == nil {
+			return append(problems, "backoff is nil")
 		}
-		// Calculate jitter range: delay * factor
-		jitterRange := float64(d) * factor
-		// Random value between -jitterRange and +jitterRange
-		jitter := (rand.Float64()*2 - 1) * jitterRange
-		result := time.Duration(float64(d) + jitter)
-		if result < 0 {
-			return 0
+		return problems
+	case checkedBackoff:
+		return b.validate(problems)
+	default:
+		return problems
+	}
+}
+
+// floorOf returns the smallest delay b can produce, if it is known.
+func floorOf(b Backoff) (time.Duration, bool) {
+	if b, ok := b.(checkedBackoff); ok {
+		return b.floor(), true
+	}
+	return 0, false
+}
+
+// bounds returns the largest WithMin and smallest WithCap in b's chain.
+func bounds(b Backoff) (lo, hi time.Duration, hasLo, hasHi bool) {
+	for b != nil {
+		switch w := b.(type) {
+		case CapBackoff:
+			if !hasHi || w.max < hi {
+				hi, hasHi = w.max, true
+			}
+			b = w.b
+		case MinBackoff:
+			if !hasLo || w.min > lo {
+				lo, hasLo = w.min, true
+			}
+			b = w.b
+		case JitterBackoff:
+			b = w.b
+		default:
+			return lo, hi, hasLo, hasHi
 		}
-		return result
-	})
+	}
+	return lo, hi, hasLo, hasHi
 }
@@ -1,6 +1,7 @@
 package retry_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -237,3 +238,61 @@ func TestBackoffFunc(t *testing.T) {
 		}
 	}
 }
+
+func TestBackoffString(t *testing.T) {
+	cases := []struct {
+		backoff  retry.Backoff
+		expected string
+	}{
+		{retry.Constant(100 * time.Millisecond), "constant(100ms)"},
+		{retry.Linear(time.Second), "linear(1s)"},
+		{retry.Exponential(100 * time.Millisecond), "exponential(100ms)"},
+		{retry.WithCap(10*time.Second, retry.Exponential(100*time.Millisecond)), "cap(10s, exponential(100ms))"},
+		{retry.WithMin(time.Second, retry.Linear(100*time.Millisecond)), "min(1s, linear(100ms))"},
+		{
+			retry.WithJitter(0.2, retry.WithCap(10*time.Second, retry.Exponential(100*time.Millisecond))),
+			"jitter(0.2, cap(10s, exponential(100ms)))",
+		},
+		{retry.WithCap(time.Second, retry.BackoffFunc(func(int) time.Duration { return 0 })), "cap(1s, custom)"},
+		{retry.WithCap(time.Second, nil), "cap(1s, nil)"},
+	}
+
+	for _, tc := range cases {
+		s, ok := tc.backoff.(fmt.Stringer)
+		if !ok {
+			t.Fatalf("%T does not implement fmt.Stringer", tc.backoff)
+		}
+		if got := s.String(); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestBackoffAccessors(t *testing.T) {
+	inner := retry.Exponential(100 * time.Millisecond)
+
+	if b := retry.Constant(time.Second).(retry.ConstantBackoff); b.Duration() != time.Second {
+		t.Errorf("expected constant duration 1s, got %v", b.Duration())
+	}
+	if b := retry.Linear(time.Second).(retry.LinearBackoff); b.Base() != time.Second {
+		t.Errorf("expected linear base 1s, got %v", b.Base())
+	}
+	if b := inner.(retry.ExponentialBackoff); b.Base() != 100*time.Millisecond {
+		t.Errorf("expected exponential base 100ms, got %v", b.Base())
+	}
+
+	c := retry.WithCap(10*time.Second, inner).(retry.CapBackoff)
+	if c.Max() != 10*time.Second || c.Unwrap() != inner {
+		t.Errorf("unexpected cap accessors: max=%v wrapped=%v", c.Max(), c.Unwrap())
+	}
+
+	m := retry.WithMin(time.Second, inner).(retry.MinBackoff)
+	if m.Min() != time.Second || m.Unwrap() != inner {
+		t.Errorf("unexpected min accessors: min=%v wrapped=%v", m.Min(), m.Unwrap())
+	}
+
+	j := retry.WithJitter(0.2, inner).(retry.JitterBackoff)
+	if j.Factor() != 0.2 || j.Unwrap() != inner {
+		t.Errorf("unexpected jitter accessors: factor=%v wrapped=%v", j.Factor(), j.Unwrap())
+	}
+}
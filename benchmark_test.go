@@ -67,6 +67,60 @@ func BenchmarkPolicy_Do(b *testing.B) {
 	}
 }
 
+func BenchmarkPolicy_DoOneRetry(b *testing.B) {
+	benchmarkOneRetry(b, New(WithClock(immediateClock{})))
+}
+
+func benchmarkOneRetry(b *testing.B, policy *Policy) {
+	ctx := context.Background()
+	fn := failOnce()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		policy.Do(ctx, fn)
+	}
+}
+
+// failOnce returns a Func that fails every other call, so each Do retries once.
+func failOnce() Func {
+	errTest := errors.New("test")
+	attempt := 0
+	return func(ctx context.Context) error {
+		attempt++
+		if attempt%2 == 1 {
+			return errTest
+		}
+		return nil
+	}
+}
+
+// TestPolicyDoAllocs locks in the cost of a policy call, so optional
+// features such as tracking and overrides stay free for calls that do not use
+// them, and shutdown support costs nothing until a call sleeps.
+func TestPolicyDoAllocs(t *testing.T) {
+	ctx := context.Background()
+	succeed := func(context.Context) error { return nil }
+	cases := []struct {
+		name   string
+		policy *Policy
+		fn     Func
+		allocs float64
+	}{
+		{"success", New(WithClock(immediateClock{})), succeed, 0},
+		{"retry without delay", New(WithClock(immediateClock{}), WithBackoff(Constant(0))), failOnce(), 1},
+		{"retry with delay", New(WithClock(immediateClock{})), failOnce(), 5},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := testing.AllocsPerRun(100, func() { _ = tc.policy.Do(ctx, tc.fn) })
+			if got > tc.allocs {
+				t.Fatalf("expected at most %v allocs per call, got %v", tc.allocs, got)
+			}
+		})
+	}
+}
+
 func BenchmarkBackoff_Exponential(b *testing.B) {
 	backoff := Exponential(100 * time.Millisecond)
 
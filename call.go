@@ -0,0 +1,34 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// CallInfo describes the Do call running a context.
+type CallInfo struct {
+	// Started is when the call began, by the policy's Clock.
+	Started time.Time
+
+	clock Clock
+}
+
+// Elapsed returns the time since the call began, by the policy's Clock. It
+// keeps growing during an attempt, so hooks can report how long the call has
+// taken so far.
+func (c CallInfo) Elapsed() time.Duration {
+	if c.clock == nil {
+		return 0
+	}
+	return c.clock.Now().Sub(c.Started)
+}
+
+// callKey is the context key for CallInfo.
+type callKey struct{}
+
+// CallFromContext returns the call running ctx. It is available to hooks,
+// middleware and fn whenever a hook or middleware is set.
+func CallFromContext(ctx context.Context) (CallInfo, bool) {
+	info, ok := ctx.Value(callKey{}).(CallInfo)
+	return info, ok
+}
@@ -0,0 +1,72 @@
+package retry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestCallFromContext(t *testing.T) {
+	t.Run("reaches hooks without middleware", func(t *testing.T) {
+		clock := newFakeClock()
+		var started []time.Time
+		var elapsed []time.Duration
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			clock.Advance(100 * time.Millisecond)
+			return errTest
+		},
+			retry.WithMaxAttempts(2),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(clock),
+			retry.OnRetry(func(ctx context.Context, _ int, _ error, _ time.Duration) {
+				call, ok := retry.CallFromContext(ctx)
+				if !ok {
+					t.Error("expected call info in OnRetry")
+				}
+				started = append(started, call.Started)
+				elapsed = append(elapsed, call.Elapsed())
+			}),
+			retry.OnGiveUp(func(ctx context.Context, _ int, _ error, _ retry.StopReason) {
+				call, _ := retry.CallFromContext(ctx)
+				started = append(started, call.Started)
+				elapsed = append(elapsed, call.Elapsed())
+			}),
+		)
+
+		if len(started) != 2 || started[0] != started[1] {
+			t.Fatalf("unexpected start times %v", started)
+		}
+		if elapsed[0] != 100*time.Millisecond || elapsed[1] != 1200*time.Millisecond {
+			t.Fatalf("unexpected elapsed %v", elapsed)
+		}
+	})
+
+	t.Run("reaches fn with middleware", func(t *testing.T) {
+		var ok bool
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			_, ok = retry.CallFromContext(ctx)
+			return nil
+		}, retry.WithMiddleware(func(next retry.Func) retry.Func { return next }))
+
+		if !ok {
+			t.Fatal("expected call info in fn")
+		}
+	})
+
+	t.Run("absent without hooks or middleware", func(t *testing.T) {
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			if _, ok := retry.CallFromContext(ctx); ok {
+				t.Fatal("expected no call info")
+			}
+			return nil
+		})
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		if got := (retry.CallInfo{}).Elapsed(); got != 0 {
+			t.Fatalf("expected 0 for a zero CallInfo, got %v", got)
+		}
+	})
+}
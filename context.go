@@ -0,0 +1,27 @@
+package retry
+
+import "context"
+
+// policyKey is the context key for a request-scoped Policy.
+type policyKey struct{}
+
+// WithPolicy returns a copy of ctx that carries p. DoCtx, and any code that
+// calls PolicyFromContext, will use p instead of the package default.
+func WithPolicy(ctx context.Context, p *Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, p)
+}
+
+// PolicyFromContext returns the policy carried by ctx, or DefaultPolicy if
+// there is none.
+func PolicyFromContext(ctx context.Context) *Policy {
+	if p, ok := ctx.Value(policyKey{}).(*Policy); ok && p != nil {
+		return p
+	}
+	return DefaultPolicy()
+}
+
+// DoCtx executes fn with retry using the policy carried by ctx,
+// falling back to the package default.
+func DoCtx(ctx context.Context, fn Func, opts ...Option) error {
+	return PolicyFromContext(ctx).Do(ctx, fn, opts...)
+}
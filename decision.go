@@ -0,0 +1,43 @@
+package retry
+
+import "time"
+
+// Decision is returned by a BeforeRetryFunc to control the upcoming retry.
+// The zero value proceeds with the planned delay.
+type Decision struct {
+	action   action
+	delay    time.Duration
+	setDelay bool
+}
+
+// action is the outcome selected by a Decision.
+type action int
+
+const (
+	actionProceed action = iota
+	actionCancel
+	actionTerminal
+)
+
+// Proceed retries after the planned delay.
+func Proceed() Decision {
+	return Decision{}
+}
+
+// ProceedAfter retries after d instead of the planned delay.
+// The delay is still capped by the remaining MaxDuration budget.
+func ProceedAfter(d time.Duration) Decision {
+	return Decision{delay: d, setDelay: true}
+}
+
+// Cancel stops retrying and returns the errors collected so far,
+// as if the If condition had rejected the error.
+func Cancel() Decision {
+	return Decision{action: actionCancel}
+}
+
+// Terminal stops retrying and returns the current error alone,
+// as if it had been wrapped with Stop.
+func Terminal() Decision {
+	return Decision{action: actionTerminal}
+}
@@ -0,0 +1,84 @@
+package retry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Description is a structured summary of a Policy, suitable for logging
+// and comparing configurations.
+type Description struct {
+	// MaxAttempts is the effective attempt budget.
+	MaxAttempts int
+	// MaxDuration is the total time budget, or 0 for none.
+	MaxDuration time.Duration
+	// Backoff describes the backoff chain, such as
+	// "jitter(0.2, cap(10s, exponential(100ms)))".
+	Backoff string
+	// Clock is "real" for the default clock, or the type of an injected one.
+	Clock string
+	// Conditional reports whether an If condition is set.
+	Conditional bool
+	// AllErrors reports whether WithAllErrors is set.
+	AllErrors bool
+	// Hooks is the number of lifecycle hooks registered.
+	Hooks int
+	// Middleware is the number of middleware registered.
+	Middleware int
+	// Remedies is the number of remediations registered with OnError.
+	Remedies int
+}
+
+// Describe returns a structured description of the policy.
+func (p *Policy) Describe() Description {
+	return p.cfg.describe()
+}
+
+// String returns a one-line summary of the policy's budget and backoff,
+// such as "attempts=3 backoff=jitter(0.2, cap(10s, exponential(100ms)))".
+func (p *Policy) String() string {
+	return p.Describe().String()
+}
+
+// String returns a one-line summary of the budget and backoff.
+func (d Description) String() string {
+	var b strings.Builder
+	b.WriteString("attempts=")
+	b.WriteString(strconv.Itoa(d.MaxAttempts))
+	if d.MaxDuration > 0 {
+		b.WriteString(" duration=")
+		b.WriteString(d.MaxDuration.String())
+	}
+	b.WriteString(" backoff=")
+	b.WriteString(d.Backoff)
+	return b.String()
+}
+
+func (c *config) describe() Description {
+	maxAttempts := c.maxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	clock := "real"
+	if _, ok := c.clock.(realClock); !ok {
+		clock = fmt.Sprintf("%T", c.clock)
+	}
+
+	h := &c.hooks
+	return Description{
+		MaxAttempts: maxAttempts,
+		MaxDuration: c.maxDuration,
+		Backoff:     backoffString(c.backoff),
+		Clock:       clock,
+		Conditional: c.condition != nil,
+		AllErrors:   c.allErrors,
+		Hooks: len(h.onAttemptStart) + len(h.onAttemptEnd) + len(h.beforeRetry) +
+			len(h.onRetry) + len(h.onSuccess) + len(h.onExhausted) + len(h.onGiveUp) +
+			len(h.onHookPanic),
+		Middleware: len(c.middleware),
+		Remedies:   len(c.remedies),
+	}
+}
@@ -0,0 +1,73 @@
+package retry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestDescribe(t *testing.T) {
+	t.Run("summarizes every option", func(t *testing.T) {
+		clock := newFakeClock()
+		p := retry.New(
+			retry.WithMaxAttempts(5),
+			retry.WithMaxDuration(30*time.Second),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(clock),
+			retry.If(func(error) bool { return true }),
+			retry.WithAllErrors(),
+			retry.OnRetry(func(context.Context, int, error, time.Duration) {}),
+			retry.OnGiveUp(func(context.Context, int, error, retry.StopReason) {}),
+			retry.WithMiddleware(func(next retry.Func) retry.Func { return next }),
+			retry.OnError(nil, func(context.Context, error) error { return nil }),
+		)
+
+		want := retry.Description{
+			MaxAttempts: 5,
+			MaxDuration: 30 * time.Second,
+			Backoff:     "constant(1s)",
+			Clock:       "*retry_test.fakeClock",
+			Conditional: true,
+			AllErrors:   true,
+			Hooks:       2,
+			Middleware:  1,
+			Remedies:    1,
+		}
+		if got := p.Describe(); got != want {
+			t.Fatalf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		want := retry.Description{
+			MaxAttempts: retry.DefaultMaxAttempts,
+			Backoff:     "exponential(100ms)",
+			Clock:       "real",
+		}
+		if got := retry.New(retry.WithMaxAttempts(0)).Describe(); got != want {
+			t.Fatalf("expected %+v, got %+v", want, got)
+		}
+	})
+}
+
+func TestPolicyString(t *testing.T) {
+	cases := []struct {
+		policy   *retry.Policy
+		expected string
+	}{
+		{retry.Default(), "attempts=3 backoff=jitter(0.2, cap(10s, exponential(100ms)))"},
+		{retry.Never(), "attempts=1 backoff=exponential(100ms)"},
+		{
+			retry.New(retry.WithMaxDuration(30*time.Second), retry.WithBackoff(retry.Linear(time.Second))),
+			"attempts=3 duration=30s backoff=linear(1s)",
+		},
+	}
+
+	for _, tc := range cases {
+		if got := tc.policy.String(); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
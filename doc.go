@@ -5,7 +5,7 @@
 //   - Dependency Injection: Inject policies at wire-up, customize behavior at call sites
 //   - Composable Backoff: Chain strategies like Exponential, WithCap, and WithJitter
 //   - Injectable Clock: Control time in tests without real sleeps
-//   - Lifecycle Hooks: OnRetry, OnSuccess, OnExhausted for observability
+//   - Lifecycle Hooks: OnAttemptStart, OnAttemptEnd, OnRetry, OnSuccess, OnExhausted, OnGiveUp for observability
 //   - Error Aggregation: Collect all errors or just the last one
 //   - Zero Dependencies: Only the Go standard library
 //
@@ -46,11 +46,24 @@
 //   - Clock: Time abstraction for testing
 //
 // Call-Level (set at each call site):
-//   - If: Condition to determine if an error should be retried
+//   - If: Condition to determine if an error should be retried; without one,
+//     every error except those wrapped with Stop is retried
 //   - OnRetry: Hook called before each retry sleep
 //   - OnSuccess: Hook called when the function succeeds
 //   - OnExhausted: Hook called when all attempts are exhausted
+//   - OnGiveUp: Hook called on every path that ends without success
+//   - OnAttemptStart, OnAttemptEnd: Hooks called around each attempt
 //   - WithAllErrors: Collect all errors instead of just the last
+//   - BeforeRetry: Hook that can cancel a retry, replace its delay, or mark the error terminal
+//   - OnError: Remediation to run between attempts for matching errors
+//   - WithMiddleware: Wrap every attempt with cross-cutting behavior
+//
+// A Policy keeps every option passed to New, including call-level ones, as
+// defaults; options passed to Do are layered on top. Use Policy.With to derive
+// a specialized policy from a shared base without modifying it:
+//
+//	base := retry.New(retry.WithMaxAttempts(3), retry.OnRetry(logRetry))
+//	payments := base.With(retry.WithMaxAttempts(5), retry.If(isTransient))
 //
 // This separation allows:
 //   - Infrastructure to control retry budgets (how many, how fast)
@@ -98,6 +111,15 @@
 //	    return time.Duration(attempt*attempt) * 100 * time.Millisecond
 //	})
 //
+// The built-in strategies are concrete types (ConstantBackoff, LinearBackoff,
+// ExponentialBackoff, CapBackoff, MinBackoff, JitterBackoff) with accessors and
+// a String method, so a policy can be logged or compared:
+//
+//	fmt.Println(retry.Default())
+//	// attempts=3 backoff=jitter(0.2, cap(10s, exponential(100ms)))
+//
+// Policy.Describe returns the same information as a structured Description.
+//
 // # Time Budgets
 //
 // Use both MaxAttempts and MaxDuration for precise control:
@@ -107,7 +129,134 @@
 //	    retry.WithMaxDuration(30*time.Second),   // OR stop after 30s total
 //	)
 //
-// The retry loop stops when either limit is reached first.
+// The retry loop stops when either limit is reached first. Delays are
+// shortened to fit the remaining budget, and a zero delay, such as from
+// Constant(0) or a SkipDelay remediation, retries immediately while any
+// budget remains.
+//
+// # JSON Configuration
+//
+// Policy and the built-in backoff types implement json.Marshaler and
+// json.Unmarshaler, so retry settings can live in service configs. Durations
+// are Go duration strings and wrappers nest the backoff they wrap:
+//
+//	{
+//	  "maxAttempts": 5,
+//	  "maxDuration": "30s",
+//	  "backoff": {"type": "cap", "max": "10s",
+//	              "backoff": {"type": "exponential", "base": "100ms"}}
+//	}
+//
+// UnmarshalBackoff decodes a backoff into a Backoff interface value, and
+// JSONSchema holds a JSON Schema document for validating configs.
+//
+// # Text Configuration
+//
+// ParsePolicy and ParseBackoff accept a compact one-line syntax, convenient
+// for flags, environment variables and operator-edited config:
+//
+//	policy, err := retry.ParsePolicy("attempts=5 duration=30s backoff=jitter(0.2,cap(10s,exp(100ms)))")
+//
+// Policy.String and the String method of every built-in backoff print the same
+// syntax, and Policy implements encoding.TextMarshaler and TextUnmarshaler.
+// Syntax errors are reported as *SyntaxError with the byte offset of the
+// problem.
+//
+// Flags and environment variables use the same syntax. PolicyFlag is a
+// flag.Value for a whole policy; FlagSet registers -prefix.max-attempts,
+// -prefix.max-duration and -prefix.backoff; FromEnv reads PREFIX_RETRY_*
+// variables:
+//
+//	flags := retry.FlagSet(flag.CommandLine, "db", retry.Default())
+//	flag.Parse()
+//	dbPolicy, err := flags.Policy()
+//
+//	apiPolicy, err := retry.FromEnv("payments", retry.Default())
+//	// PAYMENTS_RETRY_MAX_ATTEMPTS, PAYMENTS_RETRY_MAX_DURATION, PAYMENTS_RETRY_BACKOFF
+//
+// FromURLValues reads retry_max, retry_timeout and retry_backoff query
+// parameters so a policy can travel in a DSN, with backoff written as a base
+// strategy followed by wrappers; ToURLValues is the inverse:
+//
+//	dsn, _ := url.Parse("postgres://db/app?retry_max=5&retry_timeout=30s&retry_backoff=exp:100ms,cap:10s,jitter:0.2")
+//	policy, err := retry.FromURLValues(dsn.Query(), "retry")
+//
+// ParseGRPCRetryPolicy converts the retryPolicy object of a gRPC service
+// config into an equivalent Policy, with a full-jitter exponential backoff and
+// an If condition matching gRPC status errors with a retryable status code,
+// without importing grpc:
+//
+//	policy, err := retry.ParseGRPCRetryPolicy([]byte(`{
+//	    "maxAttempts": 4, "initialBackoff": "0.1s", "maxBackoff": "1s",
+//	    "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]
+//	}`))
+//
+// # Registry
+//
+// A Registry holds named policies loaded from a JSON file mapping names to
+// policies. Watch polls the file and swaps in the new set atomically: calls
+// already running finish under the old policy, and later lookups see the new
+// one. Successful reloads are reported to OnReload hooks; failed reloads keep
+// the previous policies and are reported to OnReloadError hooks:
+//
+//	registry := retry.NewRegistry(retry.OnReloadError(logReloadError))
+//	if err := registry.LoadFile("/etc/app/retry.json"); err != nil {
+//	    return err
+//	}
+//	go registry.Watch(ctx, "/etc/app/retry.json", 10*time.Second)
+//
+//	err := registry.Do(ctx, "db", fn)
+//
+// Policies registered in code with Register are the base for file entries of
+// the same name, so their hooks and conditions survive reloads. The policy
+// served for such a name shares the registered policy's overrides, shutdown
+// and in-flight tracking.
+//
+// # Runtime Overrides
+//
+// Policy.SetOverrides adjusts a live policy without rebuilding it: lower the
+// attempt limit (1 disables retries), scale every delay, or pause retries
+// until resumed. Overrides apply atomically to every Do call on that policy,
+// including calls already running:
+//
+//	registry.Get("db").SetOverrides(retry.Overrides{MaxAttempts: 1})
+//
+// The retryadmin package exposes a Registry's policies and these controls
+// over HTTP for use during incidents.
+//
+// # In-Flight Inspection
+//
+// Calls made with WithTracking are recorded while they run: name, attempt,
+// last error, next wake-up time and elapsed time. Policy.InFlight and
+// Registry.InFlight return snapshots, and retryadmin.InFlightHandler renders
+// them as HTML or JSON:
+//
+//	policy := retry.New(retry.WithTracking("db"))
+//	http.Handle("/debug/retries", retryadmin.InFlightHandler(policy))
+//
+// # Shutdown
+//
+// Policy.Shutdown stops retries, for example on SIGTERM. Sleeping and paused
+// retry loops wake at once, running attempts finish, and calls that fail
+// return an error wrapping ErrShuttingDown and the last error. Shutdown
+// blocks until every Do call on the policy has returned or its context is
+// done:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	err := policy.Shutdown(ctx)
+//
+// # Validation
+//
+// New accepts any configuration. Use NewChecked, or Policy.Validate, to catch
+// mistakes such as negative attempts or durations, a nil Backoff or Clock,
+// jitter factors outside [0, 1], a cap below a min, or a MaxDuration shorter
+// than the minimum backoff delay:
+//
+//	policy, err := retry.NewChecked(opts...)
+//	if err != nil {
+//	    return err // one *retry.ConfigError per problem
+//	}
 //
 // # Lifecycle Hooks
 //
@@ -129,6 +278,135 @@
 //	    }),
 //	)
 //
+// Hooks accumulate: every OnRetry, OnSuccess or other hook option adds to the
+// hooks already registered, and they run in order. Use ReplaceHooks to discard
+// hooks added by earlier options. A hook that panics is recovered so it cannot
+// crash the retry loop, and the panic is passed to any OnHookPanic hooks so it
+// can be logged or counted. Hooks can read when the call started, and how long
+// it has taken so far, with CallFromContext.
+//
+// OnExhausted only fires when the attempt or time budget runs out. OnGiveUp
+// fires on every path that ends without success, with a StopReason saying why
+// (exhausted, max_duration, condition, terminal, canceled, context, remedy,
+// shutdown).
+//
+// OnAttemptStart and OnAttemptEnd fire around every attempt, including the
+// first, so per-attempt latency can be recorded:
+//
+//	retry.OnAttemptEnd(func(ctx context.Context, attempt int, err error, d time.Duration) {
+//	    metrics.Observe("attempt_seconds", d.Seconds())
+//	})
+//
+// # Retry Decisions
+//
+// OnRetry only observes. BeforeRetry returns a Decision that controls the
+// upcoming retry, so cross-cutting policies can plug into the loop:
+//
+//	retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+//	    switch {
+//	    case flags.Maintenance():
+//	        return retry.Cancel()        // give up, returning errors so far
+//	    case errors.Is(err, ErrQuota):
+//	        return retry.Terminal()      // stop as if wrapped with Stop
+//	    case tenant.Throttled(ctx):
+//	        return retry.ProceedAfter(5 * time.Second)
+//	    }
+//	    return retry.Proceed()
+//	})
+//
+// # Remediation
+//
+// Some failures need a corrective action before the next attempt can succeed.
+// OnError runs a remediation between attempts when its condition matches:
+//
+//	err := policy.Do(ctx, fn,
+//	    retry.OnError(isUnauthorized, func(ctx context.Context, err error) error {
+//	        return auth.Refresh(ctx)
+//	    }, retry.SkipDelay(), retry.StopOnFailure()),
+//	)
+//
+// SkipDelay retries immediately after a successful remediation. StopOnFailure
+// stops the loop if the remediation fails; otherwise the failure is ignored.
+//
+// # Middleware
+//
+// Middleware wraps every attempt, rather than the whole loop, for tracing
+// spans, panic recovery, rate limiting or request signing:
+//
+//	func tracing(next retry.Func) retry.Func {
+//	    return func(ctx context.Context) error {
+//	        info, _ := retry.AttemptFromContext(ctx)
+//	        ctx, span := tracer.Start(ctx, "attempt", "n", info.Attempt)
+//	        defer span.End()
+//	        return next(ctx)
+//	    }
+//	}
+//
+//	err := policy.Do(ctx, fn, retry.WithMiddleware(tracing, rateLimit))
+//
+// The first middleware is the outermost, and middleware from earlier options
+// (such as the policy's) wraps middleware from later ones.
+//
+// # Named Operations
+//
+// WithName and WithLabels describe what a call is retrying. Hooks,
+// middleware and fn read them with OperationFromContext, a failed call
+// returns an *OperationError that prefixes the error with them, and the
+// logging, metrics, tracing and tracking integrations use them as
+// dimensions:
+//
+//	err := policy.Do(ctx, fn,
+//	    retry.WithName("db.query"),
+//	    retry.WithLabels(map[string]string{"region": "us-east-1"}),
+//	)
+//	// db.query [region=us-east-1]: connection refused
+//
+// # Logging
+//
+// The retryslog package logs attempts, retries, success after retrying and
+// giving up through a *slog.Logger, with consistent attribute names,
+// per-event levels and sampling:
+//
+//	policy := retry.New(retryslog.Log(logger, retryslog.WithOperation("db")))
+//
+// Records carry the call's WithName and WithLabels as the operation attribute
+// and a labels group. Options combines several options into one, which is how
+// such integrations install their hooks and middleware together.
+//
+// # Tracing
+//
+// WithTracer traces each call through the small, dependency-free Tracer and
+// Span interfaces: a "retry.Do" span for the call, with child spans
+// "retry.attempt" per attempt and "retry.sleep" per wait. fn runs under its
+// attempt's span, so its own spans nest beneath it. The examples/otelretry
+// module adapts OpenTelemetry:
+//
+//	policy := retry.New(retry.WithTracer(otelretry.New(otel.Tracer("myapp"))))
+//
+// # Profiling
+//
+// WithRuntimeTrace makes retries visible to go tool trace and pprof: each
+// call runs in a runtime/trace task, attempts and sleeps in regions, and
+// attempts carry the pprof labels retry.operation and retry.attempt:
+//
+//	policy := retry.New(retry.WithRuntimeTrace("db.query"))
+//
+// # Metrics
+//
+// The retrymetrics package counts calls, attempts, retries, successes and
+// give-ups per stop reason, with histograms of attempts per call, call
+// latency and sleeps, per named operation. It publishes them through expvar
+// and serves the OpenMetrics text format for Prometheus:
+//
+//	metrics := retrymetrics.NewCollector()
+//	metrics.Publish("retry")
+//	http.Handle("/metrics", metrics.Handler())
+//
+//	policy := retry.New(metrics.Instrument("db"))
+//
+// Instrument("") records each call under its WithName name instead, and
+// WithLabels labels become extra OpenMetrics labels.
+//
 // # Error Aggregation
 //
 // By default, only the last error is returned. Use WithAllErrors to collect all:
@@ -170,6 +448,18 @@
 //	    assert.Len(t, clock.sleeps, 2) // 2 sleeps between 3 attempts
 //	}
 //
+// Depend on the Retrier interface rather than *Policy so tests can inject a
+// double. NoRetry runs fn exactly once, and the retrytest package provides a
+// Recorder that scripts attempt outcomes and records each call's options:
+//
+//	recorder := &retrytest.Recorder{Outcomes: []error{errTransient, nil}}
+//	svc := NewUserService(recorder)
+//
+//	_ = svc.Fetch(ctx)
+//
+//	call := recorder.Calls()[0]
+//	assert.True(t, call.Retries(errTransient)) // called with If(isTransient)
+//
 // # Pre-Built Policies
 //
 // The package provides convenience functions for common configurations:
@@ -177,6 +467,32 @@
 //	retry.Never()   // No retries, just run once
 //	retry.Default() // Sensible defaults (3 attempts, exponential backoff with jitter)
 //
+// # Package Default
+//
+// The package-level Do uses the policy returned by DefaultPolicy, which starts
+// as Default(). An application can set one default at startup that every
+// library calling retry.Do picks up:
+//
+//	retry.SetDefault(retry.New(
+//	    retry.WithMaxAttempts(5),
+//	    retry.WithMaxDuration(30*time.Second),
+//	))
+//
+// SetDefault swaps the policy atomically and is safe to call concurrently
+// with Do.
+//
+// # Context-Scoped Policies
+//
+// Code that only has a context can still pick up an injected policy.
+// WithPolicy attaches a policy to a context, and DoCtx uses it, falling back
+// to the package default:
+//
+//	// In an admin handler: no retries for anything this request calls
+//	ctx = retry.WithPolicy(ctx, retry.Never())
+//
+//	// Deep in a helper
+//	err := retry.DoCtx(ctx, fn)
+//
 // # Best Practices
 //
 // 1. Inject policies, customize at call sites:
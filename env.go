@@ -0,0 +1,84 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromEnv builds a policy from environment variables named with the given
+// prefix, applied on top of defaults (or DefaultPolicy if it is nil):
+//
+//	PREFIX_RETRY_MAX_ATTEMPTS  integer
+//	PREFIX_RETRY_MAX_DURATION  Go duration, such as 30s
+//	PREFIX_RETRY_BACKOFF       backoff in ParseBackoff syntax
+//
+// The prefix is upper-cased, and dashes and dots become underscores; an empty
+// prefix reads RETRY_MAX_ATTEMPTS and so on. Unset or empty variables keep the
+// default. Every invalid variable is reported, naming the variable.
+func FromEnv(prefix string, defaults *Policy) (*Policy, error) {
+	if defaults == nil {
+		defaults = DefaultPolicy()
+	}
+	base := envPrefix(prefix)
+
+	var opts []Option
+	var errs []error
+	lookup := func(suffix string, parse func(string) (Option, error)) {
+		name := base + suffix
+		v, ok := os.LookupEnv(name)
+		if !ok || v == "" {
+			return
+		}
+		opt, err := parse(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("retry: %s=%q: %w", name, v, err))
+			return
+		}
+		opts = append(opts, opt)
+	}
+
+	lookup("MAX_ATTEMPTS", func(v string) (Option, error) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.New("not an integer")
+		}
+		return WithMaxAttempts(n), nil
+	})
+	lookup("MAX_DURATION", func(v string) (Option, error) {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, errors.New("not a duration such as 30s")
+		}
+		return WithMaxDuration(d), nil
+	})
+	lookup("BACKOFF", func(v string) (Option, error) {
+		b, err := ParseBackoff(v)
+		if err != nil {
+			return nil, err
+		}
+		return WithBackoff(b), nil
+	})
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	p := defaults.With(opts...)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// envPrefix returns the variable name prefix for FromEnv, such as
+// "PAYMENTS_RETRY_".
+func envPrefix(prefix string) string {
+	if prefix == "" {
+		return "RETRY_"
+	}
+	prefix = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(prefix))
+	return strings.TrimSuffix(prefix, "_") + "_RETRY_"
+}
@@ -0,0 +1,82 @@
+package retry_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Run("reads prefixed variables", func(t *testing.T) {
+		t.Setenv("PAYMENTS_API_RETRY_MAX_ATTEMPTS", "5")
+		t.Setenv("PAYMENTS_API_RETRY_MAX_DURATION", "30s")
+		t.Setenv("PAYMENTS_API_RETRY_BACKOFF", "cap(10s, exp(100ms))")
+
+		p, err := retry.FromEnv("payments-api", retry.New(retry.WithAllErrors()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d := p.Describe()
+		if d.MaxAttempts != 5 || d.MaxDuration != 30*time.Second || d.Backoff != "cap(10s, exponential(100ms))" {
+			t.Fatalf("unexpected policy %+v", d)
+		}
+		if !d.AllErrors {
+			t.Fatal("expected defaults' options to be kept")
+		}
+	})
+
+	t.Run("unset variables keep defaults", func(t *testing.T) {
+		t.Setenv("RETRY_MAX_ATTEMPTS", "")
+
+		p, err := retry.FromEnv("", retry.Never())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.String() != retry.Never().String() {
+			t.Fatalf("expected %s, got %s", retry.Never(), p)
+		}
+	})
+
+	t.Run("nil defaults use the default policy", func(t *testing.T) {
+		t.Setenv("JOBS_RETRY_MAX_ATTEMPTS", "4")
+
+		p, err := retry.FromEnv("jobs", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := p.Describe().Backoff; got != retry.DefaultPolicy().Describe().Backoff {
+			t.Fatalf("expected default backoff, got %s", got)
+		}
+	})
+
+	t.Run("reports every invalid variable", func(t *testing.T) {
+		t.Setenv("DB_RETRY_MAX_ATTEMPTS", "many")
+		t.Setenv("DB_RETRY_MAX_DURATION", "forever")
+		t.Setenv("DB_RETRY_BACKOFF", "fib(1s)")
+
+		_, err := retry.FromEnv("db", nil)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		for _, want := range []string{
+			`DB_RETRY_MAX_ATTEMPTS="many": not an integer`,
+			`DB_RETRY_MAX_DURATION="forever": not a duration`,
+			`DB_RETRY_BACKOFF="fib(1s)": retry: syntax error at offset 0`,
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q, got:\n%v", want, err)
+			}
+		}
+	})
+
+	t.Run("validates", func(t *testing.T) {
+		t.Setenv("DB_RETRY_MAX_DURATION", "-1s")
+
+		_, err := retry.FromEnv("db", nil)
+		if err == nil || !strings.Contains(err.Error(), "invalid MaxDuration") {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+	})
+}
@@ -2,8 +2,11 @@ package retry_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/bjaus/retry"
@@ -52,6 +55,339 @@ func ExampleNew() {
 	// Attempts: 3
 }
 
+// ExamplePolicy_With demonstrates deriving a policy from a shared base.
+func ExamplePolicy_With() {
+	base := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+		retry.OnExhausted(func(ctx context.Context, attempts int, err error) {
+			fmt.Printf("Exhausted after %d attempts\n", attempts)
+		}),
+	)
+	payments := base.With(retry.WithMaxAttempts(5))
+
+	_ = base.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("always fails")
+	})
+	_ = payments.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("always fails")
+	})
+
+	// Output:
+	// Exhausted after 3 attempts
+	// Exhausted after 5 attempts
+}
+
+// ExampleSetDefault demonstrates configuring the policy used by retry.Do.
+func ExampleSetDefault() {
+	prev := retry.DefaultPolicy()
+	defer retry.SetDefault(prev)
+
+	// At startup
+	retry.SetDefault(retry.New(
+		retry.WithMaxAttempts(2),
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+	))
+
+	// In any library using the package-level Do
+	attempts := 0
+	_ = retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errors.New("always fails")
+	})
+
+	fmt.Println("Attempts:", attempts)
+
+	// Output:
+	// Attempts: 2
+}
+
+// ExampleWithPolicy demonstrates a request-scoped policy override.
+func ExampleWithPolicy() {
+	// An admin handler disables retries for everything it calls.
+	ctx := retry.WithPolicy(context.Background(), retry.Never())
+
+	// Deep helper code only has ctx.
+	attempts := 0
+	_ = retry.DoCtx(ctx, func(ctx context.Context) error {
+		attempts++
+		return errors.New("always fails")
+	})
+
+	fmt.Println("Attempts:", attempts)
+
+	// Output:
+	// Attempts: 1
+}
+
+// ExampleNewChecked demonstrates rejecting a misconfigured policy.
+func ExampleNewChecked() {
+	_, err := retry.NewChecked(
+		retry.WithMaxAttempts(-1),
+		retry.WithBackoff(retry.WithJitter(5, retry.Exponential(100*time.Millisecond))),
+	)
+
+	fmt.Println(err)
+
+	// Output:
+	// retry: invalid MaxAttempts: -1 is negative
+	// retry: invalid Backoff: jitter factor 5 is outside [0, 1]
+}
+
+// ExamplePolicy_String demonstrates logging a policy's configuration.
+func ExamplePolicy_String() {
+	policy := retry.New(
+		retry.WithMaxAttempts(5),
+		retry.WithMaxDuration(30*time.Second),
+		retry.WithBackoff(retry.WithJitter(0.2, retry.WithCap(10*time.Second, retry.Exponential(100*time.Millisecond)))),
+	)
+
+	fmt.Println(policy)
+
+	// Output:
+	// attempts=5 duration=30s backoff=jitter(0.2, cap(10s, exponential(100ms)))
+}
+
+// ExamplePolicy_UnmarshalJSON demonstrates loading a policy from JSON configuration.
+func ExamplePolicy_UnmarshalJSON() {
+	config := []byte(`{
+		"maxAttempts": 5,
+		"maxDuration": "30s",
+		"backoff": {
+			"type": "cap", "max": "10s",
+			"backoff": {"type": "exponential", "base": "100ms"}
+		}
+	}`)
+
+	var policy retry.Policy
+	if err := json.Unmarshal(config, &policy); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println(&policy)
+
+	// Output:
+	// attempts=5 duration=30s backoff=cap(10s, exponential(100ms))
+}
+
+// ExampleParsePolicy demonstrates configuring a policy from one line of text.
+func ExampleParsePolicy() {
+	policy, err := retry.ParsePolicy("attempts=5 duration=30s backoff=jitter(0.2,cap(10s,exp(100ms)))")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println(policy)
+
+	// Output:
+	// attempts=5 duration=30s backoff=jitter(0.2, cap(10s, exponential(100ms)))
+}
+
+// ExampleParseBackoff demonstrates a syntax error with its position.
+func ExampleParseBackoff() {
+	_, err := retry.ParseBackoff("cap(10s exp(100ms))")
+
+	fmt.Println(err)
+
+	// Output:
+	// retry: syntax error at offset 8 in "cap(10s exp(100ms))": expected ',', found 'e'
+}
+
+// ExampleFlagSet demonstrates configuring a policy from command-line flags.
+func ExampleFlagSet() {
+	fs := flag.NewFlagSet("worker", flag.ContinueOnError)
+	flags := retry.FlagSet(fs, "db", retry.Default())
+
+	_ = fs.Parse([]string{"-db.max-attempts=5", "-db.backoff=exp(50ms)"})
+
+	policy, err := flags.Policy()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println(policy)
+
+	// Output:
+	// attempts=5 backoff=exponential(50ms)
+}
+
+// ExampleFromURLValues demonstrates reading a policy from a connection string.
+func ExampleFromURLValues() {
+	dsn, _ := url.Parse("postgres://db/app?sslmode=disable&retry_max=5&retry_timeout=30s&retry_backoff=exp:100ms,cap:10s,jitter:0.2")
+
+	policy, err := retry.FromURLValues(dsn.Query(), "retry")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println(policy)
+
+	values, _ := retry.ToURLValues(policy, "retry")
+	fmt.Println(values.Encode())
+
+	// Output:
+	// attempts=5 duration=30s backoff=jitter(0.2, cap(10s, exponential(100ms)))
+	// retry_backoff=exp%3A100ms%2Ccap%3A10s%2Cjitter%3A0.2&retry_max=5&retry_timeout=30s
+}
+
+// ExampleRegistry demonstrates named policies loaded from configuration.
+func ExampleRegistry() {
+	registry := retry.NewRegistry()
+	err := registry.Load([]byte(`{
+		"db":           {"maxAttempts": 5, "backoff": {"type": "exponential", "base": "50ms"}},
+		"payments-api": {"maxAttempts": 2, "maxDuration": "10s"}
+	}`))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	for _, name := range registry.Names() {
+		fmt.Printf("%s: %s\n", name, registry.Get(name))
+	}
+
+	// Output:
+	// db: attempts=5 backoff=exponential(50ms)
+	// payments-api: attempts=2 duration=10s backoff=exponential(100ms)
+}
+
+// ExamplePolicy_SetOverrides demonstrates a runtime kill switch.
+func ExamplePolicy_SetOverrides() {
+	policy := retry.New(retry.WithMaxAttempts(5), retry.WithBackoff(retry.Constant(time.Millisecond)))
+	policy.SetOverrides(retry.Overrides{MaxAttempts: 1})
+
+	attempts := 0
+	_ = policy.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errors.New("unavailable")
+	})
+
+	fmt.Println("Attempts:", attempts)
+
+	// Output:
+	// Attempts: 1
+}
+
+// ExampleWithTracking demonstrates inspecting calls in flight.
+func ExampleWithTracking() {
+	policy := retry.New(retry.WithTracking("inventory.reserve"))
+
+	_ = policy.Do(context.Background(), func(ctx context.Context) error {
+		for _, call := range policy.InFlight() {
+			fmt.Printf("%s: attempt %d\n", call.Name, call.Attempt)
+		}
+		return nil
+	})
+
+	fmt.Println("In flight after return:", len(policy.InFlight()))
+
+	// Output:
+	// inventory.reserve: attempt 1
+	// In flight after return: 0
+}
+
+// ExampleWithName demonstrates naming a call for errors, hooks and integrations.
+func ExampleWithName() {
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("connection refused")
+	},
+		retry.WithMaxAttempts(1),
+		retry.WithName("inventory.reserve"),
+		retry.WithLabels(map[string]string{"region": "us-east-1"}),
+		retry.OnGiveUp(func(ctx context.Context, attempts int, err error, reason retry.StopReason) {
+			op, _ := retry.OperationFromContext(ctx)
+			fmt.Printf("%s gave up: %s\n", op.Name, reason)
+		}),
+	)
+
+	fmt.Println(err)
+
+	// Output:
+	// inventory.reserve gave up: exhausted
+	// inventory.reserve [region=us-east-1]: connection refused
+}
+
+// ExamplePolicy_Shutdown demonstrates stopping retries on shutdown.
+func ExamplePolicy_Shutdown() {
+	policy := retry.New(retry.WithBackoff(retry.Constant(time.Hour)))
+
+	sleeping := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- policy.Do(context.Background(), func(ctx context.Context) error {
+			return errors.New("connection refused")
+		}, retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+			close(sleeping)
+		}))
+	}()
+	<-sleeping
+
+	// On SIGTERM: wake sleeping retries and wait for calls to return.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := policy.Shutdown(ctx); err != nil {
+		fmt.Println("Shutdown:", err)
+	}
+
+	err := <-done
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, retry.ErrShuttingDown))
+
+	// Output:
+	// retry: policy is shutting down: connection refused
+	// true
+}
+
+// ExampleWithTracer demonstrates adapting a tracing library to Tracer.
+func ExampleWithTracer() {
+	policy := retry.New(
+		retry.WithMaxAttempts(2),
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+		retry.WithTracer(printTracer{}),
+	)
+
+	_ = policy.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("unavailable")
+	})
+
+	// Output:
+	// start retry.Do
+	// start retry.attempt
+	// end retry.attempt: unavailable
+	// start retry.sleep
+	// end retry.sleep
+	// start retry.attempt
+	// end retry.attempt: unavailable
+	// end retry.Do: unavailable
+}
+
+// printTracer is a minimal Tracer that prints span boundaries.
+type printTracer struct{}
+
+func (printTracer) Start(ctx context.Context, name string) (context.Context, retry.Span) {
+	fmt.Println("start", name)
+	return ctx, &printSpan{name: name}
+}
+
+type printSpan struct {
+	name string
+	err  error
+}
+
+func (s *printSpan) SetAttributes(...retry.Attribute) {}
+func (s *printSpan) RecordError(err error)            { s.err = err }
+func (s *printSpan) End() {
+	if s.err != nil {
+		fmt.Printf("end %s: %v\n", s.name, s.err)
+		return
+	}
+	fmt.Println("end", s.name)
+}
+
 // ExampleNever demonstrates a policy that does not retry.
 func ExampleNever() {
 	policy := retry.Never()
@@ -226,6 +562,91 @@ func ExampleOnExhausted() {
 	// Exhausted after 3 attempts: always fails
 }
 
+// ExampleOnGiveUp demonstrates observing every way the loop can give up.
+func ExampleOnGiveUp() {
+	_ = retry.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("bad request")
+	},
+		retry.If(func(err error) bool { return false }),
+		retry.OnGiveUp(func(ctx context.Context, attempts int, err error, reason retry.StopReason) {
+			fmt.Printf("Gave up after %d attempt(s): %v (%s)\n", attempts, err, reason)
+		}),
+	)
+
+	// Output:
+	// Gave up after 1 attempt(s): bad request (condition)
+}
+
+// ExampleOnHookPanic demonstrates reporting a panicking hook.
+func ExampleOnHookPanic() {
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		return nil
+	},
+		retry.OnSuccess(func(ctx context.Context, attempts int) {
+			panic("metrics client not initialized")
+		}),
+		retry.OnHookPanic(func(ctx context.Context, recovered any) {
+			fmt.Println("Hook panicked:", recovered)
+		}),
+	)
+
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Hook panicked: metrics client not initialized
+	// Error: <nil>
+}
+
+// ExampleOnAttemptEnd demonstrates measuring each attempt.
+func ExampleOnAttemptEnd() {
+	attempts := 0
+	_ = retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	},
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+		retry.OnAttemptEnd(func(ctx context.Context, attempt int, err error, d time.Duration) {
+			fmt.Printf("Attempt %d: %v\n", attempt, err)
+		}),
+	)
+
+	// Output:
+	// Attempt 1: temporary failure
+	// Attempt 2: <nil>
+}
+
+// ExampleWithMiddleware demonstrates wrapping each attempt.
+func ExampleWithMiddleware() {
+	logAttempt := func(next retry.Func) retry.Func {
+		return func(ctx context.Context) error {
+			info, _ := retry.AttemptFromContext(ctx)
+			err := next(ctx)
+			fmt.Printf("Attempt %d/%d: %v\n", info.Attempt, info.MaxAttempts, err)
+			return err
+		}
+	}
+
+	attempts := 0
+	_ = retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	},
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+		retry.WithMiddleware(logAttempt),
+	)
+
+	// Output:
+	// Attempt 1/3: temporary failure
+	// Attempt 2/3: <nil>
+}
+
 // ExampleWithAllErrors demonstrates collecting all errors.
 func ExampleWithAllErrors() {
 	attempt := 0
@@ -246,6 +667,61 @@ func ExampleWithAllErrors() {
 	// Error string contains all: true
 }
 
+// ExampleBeforeRetry demonstrates vetoing retries with a decision hook.
+func ExampleBeforeRetry() {
+	maintenance := true
+
+	attempts := 0
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errors.New("service unavailable")
+	},
+		retry.WithMaxAttempts(5),
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+		retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+			if maintenance {
+				return retry.Cancel()
+			}
+			return retry.Proceed()
+		}),
+	)
+
+	fmt.Println("Error:", err)
+	fmt.Println("Attempts:", attempts)
+
+	// Output:
+	// Error: service unavailable
+	// Attempts: 1
+}
+
+// ExampleOnError demonstrates refreshing a credential before retrying.
+func ExampleOnError() {
+	errExpired := errors.New("token expired")
+	token := "stale"
+
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		if token == "stale" {
+			return errExpired
+		}
+		return nil
+	},
+		retry.WithBackoff(retry.Constant(time.Second)),
+		retry.OnError(func(err error) bool {
+			return errors.Is(err, errExpired)
+		}, func(ctx context.Context, err error) error {
+			token = "fresh"
+			fmt.Println("Refreshed token")
+			return nil
+		}, retry.SkipDelay()),
+	)
+
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Refreshed token
+	// Error: <nil>
+}
+
 // ExampleConstant demonstrates constant backoff.
 func ExampleConstant() {
 	b := retry.Constant(100 * time.Millisecond)
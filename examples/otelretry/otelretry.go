@@ -0,0 +1,70 @@
+// Package otelretry adapts an OpenTelemetry tracer to retry.Tracer.
+//
+// It lives in its own module so the retry package stays free of
+// dependencies; copy it, or use it as a model for other tracing APIs:
+//
+//	policy := retry.New(retry.WithTracer(otelretry.New(otel.Tracer("myapp"))))
+package otelretry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bjaus/retry"
+)
+
+// New returns a retry.Tracer that starts OpenTelemetry spans with t.
+func New(t trace.Tracer) retry.Tracer {
+	return tracer{t}
+}
+
+type tracer struct {
+	t trace.Tracer
+}
+
+func (t tracer) Start(ctx context.Context, name string) (context.Context, retry.Span) {
+	ctx, s := t.t.Start(ctx, name)
+	return ctx, span{s}
+}
+
+type span struct {
+	s trace.Span
+}
+
+func (s span) SetAttributes(attrs ...retry.Attribute) {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		kvs = append(kvs, keyValue(a))
+	}
+	s.s.SetAttributes(kvs...)
+}
+
+func (s span) RecordError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.s.End()
+}
+
+// keyValue converts a retry attribute. Durations are recorded in seconds.
+func keyValue(a retry.Attribute) attribute.KeyValue {
+	switch v := a.Value.(type) {
+	case int:
+		return attribute.Int(a.Key, v)
+	case bool:
+		return attribute.Bool(a.Key, v)
+	case string:
+		return attribute.String(a.Key, v)
+	case time.Duration:
+		return attribute.Float64(a.Key, v.Seconds())
+	default:
+		return attribute.String(a.Key, fmt.Sprint(v))
+	}
+}
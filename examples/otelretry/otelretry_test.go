@@ -0,0 +1,58 @@
+package otelretry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/examples/otelretry"
+)
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	policy := retry.New(
+		retry.WithMaxAttempts(2),
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+		retry.WithTracer(otelretry.New(provider.Tracer("test"))),
+	)
+	_ = policy.Do(context.Background(), func(context.Context) error {
+		return errors.New("unavailable")
+	})
+
+	spans := exporter.GetSpans()
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(spans))
+	}
+
+	// Spans are exported as they end: attempt, sleep, attempt, call.
+	do := spans[3]
+	if do.Name != retry.SpanDo || do.Status.Code != codes.Error {
+		t.Fatalf("unexpected call span %s %v", do.Name, do.Status)
+	}
+	for _, s := range spans[:3] {
+		if s.Parent.SpanID() != do.SpanContext.SpanID() {
+			t.Errorf("expected %s to be a child of %s", s.Name, retry.SpanDo)
+		}
+	}
+	sleep := spans[1]
+	want := attribute.Float64(retry.AttrDelay, 0.001)
+	found := false
+	for _, kv := range sleep.Attributes {
+		if kv == want {
+			found = true
+		}
+	}
+	if sleep.Name != retry.SpanSleep || !found {
+		t.Fatalf("unexpected sleep span %s %v", sleep.Name, sleep.Attributes)
+	}
+}
@@ -0,0 +1,117 @@
+package retry
+
+import (
+	"flag"
+	"time"
+)
+
+// PolicyFlag is a flag.Value that holds a Policy written in the syntax
+// accepted by ParsePolicy:
+//
+//	f := &retry.PolicyFlag{Policy: retry.Default()}
+//	flag.Var(f, "retry", "retry policy")
+//	// -retry="attempts=5 backoff=exp(100ms)"
+//
+// Fields absent from the flag keep the values of the current Policy, or New's
+// defaults if it is nil, as do call-level options such as hooks.
+type PolicyFlag struct {
+	Policy *Policy
+}
+
+var _ flag.Getter = (*PolicyFlag)(nil)
+
+// String implements flag.Value.
+func (f *PolicyFlag) String() string {
+	if f == nil || f.Policy == nil {
+		return ""
+	}
+	return f.Policy.String()
+}
+
+// Set implements flag.Value.
+func (f *PolicyFlag) Set(s string) error {
+	p := &Policy{}
+	if f.Policy != nil {
+		p.cfg = f.Policy.cfg
+	}
+	if err := p.UnmarshalText([]byte(s)); err != nil {
+		return err
+	}
+	f.Policy = p
+	return nil
+}
+
+// Get implements flag.Getter and returns the *Policy.
+func (f *PolicyFlag) Get() any {
+	return f.Policy
+}
+
+// Flags holds the retry flags registered by FlagSet.
+type Flags struct {
+	defaults    *Policy
+	maxAttempts int
+	maxDuration time.Duration
+	backoff     backoffFlag
+}
+
+// FlagSet registers retry flags on fs, named with the given prefix:
+//
+//	-prefix.max-attempts  int
+//	-prefix.max-duration  duration
+//	-prefix.backoff       backoff in ParseBackoff syntax
+//
+// An empty prefix registers -max-attempts, -max-duration and -backoff.
+// Flag defaults come from defaults, or DefaultPolicy if it is nil.
+// Call Flags.Policy after fs.Parse to build the configured policy.
+func FlagSet(fs *flag.FlagSet, prefix string, defaults *Policy) *Flags {
+	if defaults == nil {
+		defaults = DefaultPolicy()
+	}
+	d := defaults.Describe()
+	f := &Flags{defaults: defaults, backoff: backoffFlag{b: defaults.cfg.backoff}}
+
+	name := func(s string) string {
+		if prefix == "" {
+			return s
+		}
+		return prefix + "." + s
+	}
+	fs.IntVar(&f.maxAttempts, name("max-attempts"), d.MaxAttempts, "maximum number of retry attempts")
+	fs.DurationVar(&f.maxDuration, name("max-duration"), d.MaxDuration, "total retry time budget (0 for none)")
+	fs.Var(&f.backoff, name("backoff"), "retry backoff, such as jitter(0.2, cap(10s, exp(100ms)))")
+	return f
+}
+
+// Policy returns the defaults with the flag values applied, validated.
+func (f *Flags) Policy() (*Policy, error) {
+	p := f.defaults.With(
+		WithMaxAttempts(f.maxAttempts),
+		WithMaxDuration(f.maxDuration),
+		WithBackoff(f.backoff.b),
+	)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// backoffFlag is a flag.Value holding a Backoff in ParseBackoff syntax.
+type backoffFlag struct {
+	b Backoff
+}
+
+func (f *backoffFlag) String() string {
+	if f == nil || f.b == nil {
+		return ""
+	}
+	return backoffString(f.b)
+}
+
+func (f *backoffFlag) Set(s string) error {
+	b, err := ParseBackoff(s)
+	if err != nil {
+		return err
+	}
+	f.b = b
+	return nil
+}
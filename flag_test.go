@@ -0,0 +1,137 @@
+package retry_test
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestPolicyFlag(t *testing.T) {
+	t.Run("parses policy text", func(t *testing.T) {
+		f := &retry.PolicyFlag{Policy: retry.New(retry.WithMaxDuration(time.Minute))}
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(f, "retry", "retry policy")
+
+		if err := fs.Parse([]string{"-retry", "attempts=5 backoff=exp(1s)"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := f.String(); got != "attempts=5 duration=1m0s backoff=exponential(1s)" {
+			t.Fatalf("unexpected policy %q", got)
+		}
+		if f.Get() != f.Policy {
+			t.Fatal("expected Get to return the policy")
+		}
+	})
+
+	t.Run("nil policy uses defaults", func(t *testing.T) {
+		var f retry.PolicyFlag
+		if f.String() != "" {
+			t.Fatalf("expected empty string, got %q", f.String())
+		}
+		if err := f.Set("attempts=2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := f.String(); got != "attempts=2 backoff=exponential(100ms)" {
+			t.Fatalf("unexpected policy %q", got)
+		}
+	})
+
+	t.Run("rejects invalid text", func(t *testing.T) {
+		original := retry.Never()
+		f := &retry.PolicyFlag{Policy: original}
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(f, "retry", "retry policy")
+
+		err := fs.Parse([]string{"-retry", "attempts=lots"})
+		if err == nil || !strings.Contains(err.Error(), `invalid integer "lots"`) {
+			t.Fatalf("expected syntax error, got %v", err)
+		}
+		if f.Policy != original {
+			t.Fatal("expected policy to be unchanged")
+		}
+	})
+}
+
+func TestFlagSet(t *testing.T) {
+	t.Run("registers prefixed flags", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := retry.FlagSet(fs, "db", retry.New(retry.WithAllErrors()))
+
+		err := fs.Parse([]string{
+			"-db.max-attempts=5",
+			"-db.max-duration=30s",
+			"-db.backoff=jitter(0.2, exp(100ms))",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		p, err := flags.Policy()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d := p.Describe()
+		if d.MaxAttempts != 5 || d.MaxDuration != 30*time.Second || d.Backoff != "jitter(0.2, exponential(100ms))" {
+			t.Fatalf("unexpected policy %+v", d)
+		}
+		if !d.AllErrors {
+			t.Fatal("expected defaults' options to be kept")
+		}
+	})
+
+	t.Run("defaults without flags", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := retry.FlagSet(fs, "", retry.Default())
+		if err := fs.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, name := range []string{"max-attempts", "max-duration", "backoff"} {
+			if fs.Lookup(name) == nil {
+				t.Fatalf("expected flag %q", name)
+			}
+		}
+		if got := fs.Lookup("backoff").DefValue; got != "jitter(0.2, cap(10s, exponential(100ms)))" {
+			t.Fatalf("unexpected backoff default %q", got)
+		}
+
+		p, err := flags.Policy()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.String() != retry.Default().String() {
+			t.Fatalf("expected %s, got %s", retry.Default(), p)
+		}
+	})
+
+	t.Run("rejects invalid backoff", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		retry.FlagSet(fs, "db", nil)
+
+		err := fs.Parse([]string{"-db.backoff=exp(1s"})
+		if err == nil || !strings.Contains(err.Error(), "expected ')'") {
+			t.Fatalf("expected syntax error, got %v", err)
+		}
+	})
+
+	t.Run("validates", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := retry.FlagSet(fs, "db", nil)
+		if err := fs.Parse([]string{"-db.max-attempts=-2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err := flags.Policy()
+		var cfgErr *retry.ConfigError
+		if !errors.As(err, &cfgErr) || cfgErr.Field != "MaxAttempts" {
+			t.Fatalf("expected MaxAttempts *ConfigError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+package retry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// grpcMaxAttempts is the limit gRPC applies to retryPolicy.maxAttempts.
+const grpcMaxAttempts = 5
+
+// grpcCodes maps gRPC status code names to their numeric values.
+var grpcCodes = map[string]int64{
+	"OK":                  0,
+	"CANCELLED":           1,
+	"UNKNOWN":             2,
+	"INVALID_ARGUMENT":    3,
+	"DEADLINE_EXCEEDED":   4,
+	"NOT_FOUND":           5,
+	"ALREADY_EXISTS":      6,
+	"PERMISSION_DENIED":   7,
+	"RESOURCE_EXHAUSTED":  8,
+	"FAILED_PRECONDITION": 9,
+	"ABORTED":             10,
+	"OUT_OF_RANGE":        11,
+	"UNIMPLEMENTED":       12,
+	"INTERNAL":            13,
+	"UNAVAILABLE":         14,
+	"DATA_LOSS":           15,
+	"UNAUTHENTICATED":     16,
+}
+
+// grpcRetryPolicy is the retryPolicy object of a gRPC service config.
+type grpcRetryPolicy struct {
+	MaxAttempts          int               `json:"maxAttempts"`
+	InitialBackoff       string            `json:"initialBackoff"`
+	MaxBackoff           string            `json:"maxBackoff"`
+	BackoffMultiplier    float64           `json:"backoffMultiplier"`
+	RetryableStatusCodes []json.RawMessage `json:"retryableStatusCodes"`
+}
+
+// ParseGRPCRetryPolicy converts the retryPolicy object of a gRPC service
+// config into a Policy with the same semantics:
+//
+//	{
+//	  "maxAttempts": 4,
+//	  "initialBackoff": "0.1s",
+//	  "maxBackoff": "1s",
+//	  "backoffMultiplier": 2,
+//	  "retryableStatusCodes": ["UNAVAILABLE", "RESOURCE_EXHAUSTED"]
+//	}
+//
+// As in gRPC, maxAttempts above 5 is treated as 5, and the delay before retry
+// n is random between 0 and min(initialBackoff * backoffMultiplier^(n-1),
+// maxBackoff). Status codes may be names, in any case, or numbers.
+//
+// The policy's If condition retries errors that carry one of the retryable
+// codes anywhere in their Unwrap chain. The code is read from a GRPCStatus()
+// method whose result has a Code() method, as gRPC status errors have, or
+// from a Code() method on the error itself. Code() may return any integer
+// type, or a string holding the code name. The grpc package itself is not
+// imported.
+//
+// The gRPC backoff cannot be encoded as JSON or text.
+func ParseGRPCRetryPolicy(data []byte) (*Policy, error) {
+	var v grpcRetryPolicy
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("retry: grpc retryPolicy: %w", err)
+	}
+
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("retry: grpc retryPolicy: %s %s", field, fmt.Sprintf(format, args...)))
+	}
+	duration := func(field, s string) time.Duration {
+		if s == "" {
+			fail(field, "is required")
+			return 0
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			fail(field, "must be a positive duration such as \"0.1s\", got %q", s)
+		}
+		return d
+	}
+
+	if v.MaxAttempts < 2 {
+		fail("maxAttempts", "must be greater than 1, got %d", v.MaxAttempts)
+	}
+	initial := duration("initialBackoff", v.InitialBackoff)
+	maxBackoff := duration("maxBackoff", v.MaxBackoff)
+	if v.BackoffMultiplier <= 0 {
+		fail("backoffMultiplier", "must be greater than 0, got %v", v.BackoffMultiplier)
+	}
+	if len(v.RetryableStatusCodes) == 0 {
+		fail("retryableStatusCodes", "must not be empty")
+	}
+	codes := make(map[int64]bool, len(v.RetryableStatusCodes))
+	for _, raw := range v.RetryableStatusCodes {
+		code, err := parseGRPCCode(raw)
+		if err != nil {
+			fail("retryableStatusCodes", "%v", err)
+			continue
+		}
+		codes[code] = true
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return New(
+		WithMaxAttempts(min(v.MaxAttempts, grpcMaxAttempts)),
+		WithBackoff(grpcBackoff{initial: initial, max: maxBackoff, multiplier: v.BackoffMultiplier}),
+		If(func(err error) bool {
+			code, ok := errorCode(err)
+			return ok && codes[code]
+		}),
+	), nil
+}
+
+// parseGRPCCode decodes a status code given as a name or a number.
+func parseGRPCCode(raw json.RawMessage) (int64, error) {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		code, ok := grpcCodes[strings.ToUpper(name)]
+		if !ok {
+			return 0, fmt.Errorf("has unknown code %q", name)
+		}
+		return code, nil
+	}
+	var n int64
+	if err := json.Unmarshal(raw, &n); err != nil || n < 0 || n > 16 {
+		return 0, fmt.Errorf("has invalid code %s", raw)
+	}
+	return n, nil
+}
+
+// errorCode finds the first error in err's tree with a gRPC status code and
+// returns the code as a number.
+func errorCode(err error) (int64, bool) {
+	for err != nil {
+		if code, ok := codeOf(err); ok {
+			return code, true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if code, ok := errorCode(e); ok {
+					return code, true
+				}
+			}
+			return 0, false
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// codeOf returns err's status code, from the status returned by its
+// GRPCStatus() method if it has one, or else from its own Code() method.
+func codeOf(err error) (int64, bool) {
+	v := reflect.ValueOf(err)
+	if status, ok := callMethod(v, "GRPCStatus"); ok {
+		switch status.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if status.IsNil() {
+				return 0, false
+			}
+		}
+		return codeValue(status)
+	}
+	return codeValue(v)
+}
+
+// codeValue calls v's Code() method, if it has one returning an integer or
+// a code name.
+func codeValue(v reflect.Value) (int64, bool) {
+	c, ok := callMethod(v, "Code")
+	if !ok {
+		return 0, false
+	}
+	switch c.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return c.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if c.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(c.Uint()), true
+	case reflect.String:
+		code, ok := grpcCodes[strings.ToUpper(c.String())]
+		return code, ok
+	default:
+		return 0, false
+	}
+}
+
+// callMethod calls v's method name if it takes no arguments and returns one
+// value.
+func callMethod(v reflect.Value, name string) (reflect.Value, bool) {
+	m := v.MethodByName(name)
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
+
+// grpcBackoff is gRPC's full-jitter exponential backoff.
+type grpcBackoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+}
+
+// Delay implements Backoff.
+func (b grpcBackoff) Delay(attempt int) time.Duration {
+	ceiling := float64(b.initial) * math.Pow(b.multiplier, float64(max(attempt, 1)-1))
+	ceiling = min(ceiling, float64(b.max))
+	return time.Duration(rand.Float64() * ceiling)
+}
+
+// String returns the backoff as grpc(initial, max, multiplier).
+func (b grpcBackoff) String() string {
+	return "grpc(" + b.initial.String() + ", " + b.max.String() + ", " +
+		strconv.FormatFloat(b.multiplier, 'g', -1, 64) + ")"
+}
+
+func (b grpcBackoff) validate(problems []string) []string {
+	return problems
+}
+
+func (b grpcBackoff) floor() time.Duration {
+	return 0
+}
@@ -0,0 +1,183 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+// code mirrors grpc's codes.Code without importing grpc.
+type code uint32
+
+const (
+	codeNotFound    code = 5
+	codeUnavailable code = 14
+)
+
+// status mirrors grpc's *status.Status.
+type status struct {
+	code code
+}
+
+func (s *status) Code() code { return s.code }
+
+// statusError mirrors the error returned by grpc's status.Error, which has a
+// GRPCStatus method but no Code method.
+type statusError struct {
+	code code
+}
+
+func (e *statusError) Error() string       { return fmt.Sprintf("rpc error: code = %d", e.code) }
+func (e *statusError) GRPCStatus() *status { return &status{code: e.code} }
+
+// nilStatusError has a GRPCStatus method returning nil.
+type nilStatusError struct{}
+
+func (nilStatusError) Error() string       { return "no status" }
+func (nilStatusError) GRPCStatus() *status { return nil }
+
+// codeError exposes its code through its own Code method.
+type codeError struct {
+	code code
+}
+
+func (e *codeError) Error() string { return fmt.Sprintf("code %d", e.code) }
+func (e *codeError) Code() code    { return e.code }
+
+// namedCodeError exposes its code as a name.
+type namedCodeError string
+
+func (e namedCodeError) Error() string { return string(e) }
+func (e namedCodeError) Code() string  { return string(e) }
+
+const grpcConfig = `{
+	"maxAttempts": 4,
+	"initialBackoff": "0.1s",
+	"maxBackoff": "1s",
+	"backoffMultiplier": 2,
+	"retryableStatusCodes": ["UNAVAILABLE", "resource_exhausted", 4]
+}`
+
+func TestParseGRPCRetryPolicy(t *testing.T) {
+	t.Run("converts settings", func(t *testing.T) {
+		p, err := retry.ParseGRPCRetryPolicy([]byte(grpcConfig))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d := p.Describe()
+		if d.MaxAttempts != 4 || d.Backoff != "grpc(100ms, 1s, 2)" || !d.Conditional {
+			t.Fatalf("unexpected policy %+v", d)
+		}
+	})
+
+	t.Run("caps attempts at 5", func(t *testing.T) {
+		p, err := retry.ParseGRPCRetryPolicy([]byte(`{
+			"maxAttempts": 10, "initialBackoff": "1s", "maxBackoff": "2s",
+			"backoffMultiplier": 1.5, "retryableStatusCodes": ["UNAVAILABLE"]
+		}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := p.Describe().MaxAttempts; got != 5 {
+			t.Fatalf("expected 5 attempts, got %d", got)
+		}
+	})
+
+	t.Run("retries only listed codes", func(t *testing.T) {
+		p, err := retry.ParseGRPCRetryPolicy([]byte(grpcConfig))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cases := []struct {
+			name string
+			err  error
+			want bool
+		}{
+			{"listed code", &statusError{code: codeUnavailable}, true},
+			{"unlisted code", &statusError{code: codeNotFound}, false},
+			{"numeric listed code", &statusError{code: 4}, true},
+			{"wrapped", fmt.Errorf("fetch: %w", &statusError{code: codeUnavailable}), true},
+			{"joined", errors.Join(errTest, &statusError{code: codeUnavailable}), true},
+			{"code method", &codeError{code: codeUnavailable}, true},
+			{"unlisted code method", &codeError{code: codeNotFound}, false},
+			{"named code", namedCodeError("RESOURCE_EXHAUSTED"), true},
+			{"nil status", nilStatusError{}, false},
+			{"no code", errTest, false},
+		}
+		for _, tc := range cases {
+			if got := p.Retryable(tc.err); got != tc.want {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+			}
+		}
+	})
+
+	t.Run("full-jitter exponential delays", func(t *testing.T) {
+		clock := newFakeClock()
+		p, err := retry.ParseGRPCRetryPolicy([]byte(`{
+			"maxAttempts": 5, "initialBackoff": "0.1s", "maxBackoff": "0.3s",
+			"backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]
+		}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_ = p.Do(context.Background(), func(ctx context.Context) error {
+			return &statusError{code: codeUnavailable}
+		}, retry.WithClock(clock))
+
+		ceilings := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+		if len(clock.sleeps) != len(ceilings) {
+			t.Fatalf("expected %d sleeps, got %v", len(ceilings), clock.sleeps)
+		}
+		for i, d := range clock.sleeps {
+			if d < 0 || d > ceilings[i] {
+				t.Errorf("sleep %d: expected within [0, %v], got %v", i+1, ceilings[i], d)
+			}
+		}
+	})
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		_, err := retry.ParseGRPCRetryPolicy([]byte(`{
+			"maxAttempts": 1,
+			"initialBackoff": "soon",
+			"backoffMultiplier": 0,
+			"retryableStatusCodes": ["UNAVAILABLE", "TEAPOT", 99]
+		}`))
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		for _, want := range []string{
+			"maxAttempts must be greater than 1, got 1",
+			`initialBackoff must be a positive duration such as "0.1s", got "soon"`,
+			"maxBackoff is required",
+			"backoffMultiplier must be greater than 0, got 0",
+			`retryableStatusCodes has unknown code "TEAPOT"`,
+			"retryableStatusCodes has invalid code 99",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q, got:\n%v", want, err)
+			}
+		}
+	})
+
+	t.Run("empty codes", func(t *testing.T) {
+		_, err := retry.ParseGRPCRetryPolicy([]byte(`{
+			"maxAttempts": 2, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 1
+		}`))
+		if err == nil || !strings.Contains(err.Error(), "retryableStatusCodes must not be empty") {
+			t.Fatalf("expected empty codes error, got %v", err)
+		}
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		if _, err := retry.ParseGRPCRetryPolicy([]byte(`{`)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
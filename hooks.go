@@ -0,0 +1,123 @@
+package retry
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// hooks holds the lifecycle hooks registered for a retry loop.
+// Each option appends to its list, so hooks from a policy and a call site
+// all run, in the order they were registered.
+type hooks struct {
+	onAttemptStart []OnAttemptStartFunc
+	onAttemptEnd   []OnAttemptEndFunc
+	beforeRetry    []BeforeRetryFunc
+	onRetry        []OnRetryFunc
+	onSuccess      []OnSuccessFunc
+	onExhausted    []OnExhaustedFunc
+	onGiveUp       []OnGiveUpFunc
+	onHookPanic    []OnHookPanicFunc
+}
+
+// clip returns a copy of h with no spare capacity in any list.
+func (h hooks) clip() hooks {
+	h.onAttemptStart = slices.Clip(h.onAttemptStart)
+	h.onAttemptEnd = slices.Clip(h.onAttemptEnd)
+	h.beforeRetry = slices.Clip(h.beforeRetry)
+	h.onRetry = slices.Clip(h.onRetry)
+	h.onSuccess = slices.Clip(h.onSuccess)
+	h.onExhausted = slices.Clip(h.onExhausted)
+	h.onGiveUp = slices.Clip(h.onGiveUp)
+	h.onHookPanic = slices.Clip(h.onHookPanic)
+	return h
+}
+
+// empty reports whether no hooks are set.
+func (h *hooks) empty() bool {
+	return len(h.onAttemptStart) == 0 && len(h.onAttemptEnd) == 0 &&
+		len(h.beforeRetry) == 0 && len(h.onRetry) == 0 && len(h.onSuccess) == 0 &&
+		len(h.onExhausted) == 0 && len(h.onGiveUp) == 0
+}
+
+func (h *hooks) attemptStart(ctx context.Context, attempt int) {
+	for _, fn := range h.onAttemptStart {
+		h.guard(ctx, func() { fn(ctx, attempt) })
+	}
+}
+
+func (h *hooks) attemptEnd(ctx context.Context, attempt int, err error, d time.Duration) {
+	for _, fn := range h.onAttemptEnd {
+		h.guard(ctx, func() { fn(ctx, attempt, err, d) })
+	}
+}
+
+// decide runs the BeforeRetry hooks in order. Each hook sees the delay chosen
+// by the hooks before it, and the first one to cancel or terminate wins.
+// A hook that panics is treated as returning Proceed.
+func (h *hooks) decide(ctx context.Context, attempt int, err error, delay time.Duration) Decision {
+	var result Decision
+	for _, fn := range h.beforeRetry {
+		var d Decision
+		h.guard(ctx, func() { d = fn(ctx, attempt, err, delay) })
+		if d.action != actionProceed {
+			return d
+		}
+		if d.setDelay {
+			delay = d.delay
+			result = ProceedAfter(delay)
+		}
+	}
+	return result
+}
+
+func (h *hooks) retry(ctx context.Context, attempt int, err error, delay time.Duration) {
+	for _, fn := range h.onRetry {
+		h.guard(ctx, func() { fn(ctx, attempt, err, delay) })
+	}
+}
+
+func (h *hooks) success(ctx context.Context, attempts int) {
+	for _, fn := range h.onSuccess {
+		h.guard(ctx, func() { fn(ctx, attempts) })
+	}
+}
+
+func (h *hooks) exhausted(ctx context.Context, attempts int, err error) {
+	for _, fn := range h.onExhausted {
+		h.guard(ctx, func() { fn(ctx, attempts, err) })
+	}
+}
+
+func (h *hooks) giveUp(ctx context.Context, attempts int, err error, reason StopReason) {
+	for _, fn := range h.onGiveUp {
+		h.guard(ctx, func() { fn(ctx, attempts, err, reason) })
+	}
+}
+
+// guard runs fn and recovers any panic, so a faulty hook cannot crash the
+// retry loop. The panic is reported to the OnHookPanic hooks.
+func (h *hooks) guard(ctx context.Context, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.hookPanic(ctx, r)
+		}
+	}()
+	fn()
+}
+
+// hookPanic reports a recovered hook panic. Panics in the OnHookPanic hooks
+// themselves are discarded.
+func (h *hooks) hookPanic(ctx context.Context, recovered any) {
+	for _, fn := range h.onHookPanic {
+		protect(func() { fn(ctx, recovered) })
+	}
+}
+
+// protect runs fn and discards any panic.
+func protect(fn func()) {
+	defer func() {
+		_ = recover()
+	}()
+	fn()
+}
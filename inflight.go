@@ -0,0 +1,129 @@
+package retry
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// InFlightCall is a snapshot of a tracked Do call that has not returned yet.
+type InFlightCall struct {
+	// Name is the name given to WithTracking.
+	Name string
+	// Attempt is the current attempt, or the last one if the call is
+	// waiting to retry.
+	Attempt int
+	// LastErr is the error of the most recent failed attempt, if any.
+	LastErr error
+	// NextWake is when the call's retry sleep ends, or zero if it is not
+	// sleeping.
+	NextWake time.Time
+	// Paused reports whether the call is held by a Paused override.
+	Paused bool
+	// Started is when the call began.
+	Started time.Time
+	// Elapsed is how long the call has been running.
+	Elapsed time.Duration
+}
+
+// InFlight returns the tracked calls currently running under the policy,
+// oldest first. Only calls made with WithTracking are recorded.
+func (p *Policy) InFlight() []InFlightCall {
+	if p.ctl == nil {
+		return nil
+	}
+	return p.ctl.flights.snapshot()
+}
+
+// tracker records a policy's tracked calls in flight.
+type tracker struct {
+	mu    sync.Mutex
+	calls map[*flight]struct{}
+}
+
+// flight is the live state of one tracked call.
+type flight struct {
+	clock Clock
+
+	mu   sync.Mutex
+	call InFlightCall
+}
+
+func (t *tracker) add(name string, clock Clock) *flight {
+	f := &flight{clock: clock, call: InFlightCall{Name: name, Started: clock.Now()}}
+	t.mu.Lock()
+	if t.calls == nil {
+		t.calls = make(map[*flight]struct{})
+	}
+	t.calls[f] = struct{}{}
+	t.mu.Unlock()
+	return f
+}
+
+func (t *tracker) remove(f *flight) {
+	t.mu.Lock()
+	delete(t.calls, f)
+	t.mu.Unlock()
+}
+
+func (t *tracker) snapshot() []InFlightCall {
+	t.mu.Lock()
+	flights := make([]*flight, 0, len(t.calls))
+	for f := range t.calls {
+		flights = append(flights, f)
+	}
+	t.mu.Unlock()
+
+	calls := make([]InFlightCall, 0, len(flights))
+	for _, f := range flights {
+		f.mu.Lock()
+		c := f.call
+		f.mu.Unlock()
+		c.Elapsed = f.clock.Now().Sub(c.Started)
+		calls = append(calls, c)
+	}
+	slices.SortFunc(calls, func(a, b InFlightCall) int { return a.Started.Compare(b.Started) })
+	return calls
+}
+
+// The update methods accept a nil flight so the retry loop can call them
+// unconditionally when tracking is off.
+
+func (f *flight) attempting(attempt int) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	f.call.Attempt = attempt
+	f.call.NextWake = time.Time{}
+	f.call.Paused = false
+	f.mu.Unlock()
+}
+
+func (f *flight) failed(err error) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	f.call.LastErr = err
+	f.mu.Unlock()
+}
+
+func (f *flight) sleeping(wake time.Time) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	f.call.NextWake = wake
+	f.mu.Unlock()
+}
+
+func (f *flight) paused() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	f.call.NextWake = time.Time{}
+	f.call.Paused = true
+	f.mu.Unlock()
+}
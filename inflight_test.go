@@ -0,0 +1,111 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestInFlight(t *testing.T) {
+	t.Run("untracked calls are not recorded", func(t *testing.T) {
+		p := retry.New(retry.WithClock(newFakeClock()))
+
+		_ = p.Do(context.Background(), func(context.Context) error {
+			if got := p.InFlight(); len(got) != 0 {
+				t.Errorf("expected no calls, got %+v", got)
+			}
+			return nil
+		})
+	})
+
+	t.Run("records tracked calls while they run", func(t *testing.T) {
+		clock := newFakeClock()
+		p := retry.New(
+			retry.WithTracking("db"),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(clock),
+		)
+
+		var snapshots [][]retry.InFlightCall
+		calls := 0
+		err := p.Do(context.Background(), func(context.Context) error {
+			calls++
+			clock.Advance(100 * time.Millisecond)
+			snapshots = append(snapshots, p.InFlight())
+			if calls < 2 {
+				return errTest
+			}
+			return nil
+		}, retry.OnRetry(func(context.Context, int, error, time.Duration) {
+			snapshots = append(snapshots, p.InFlight())
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(snapshots) != 3 {
+			t.Fatalf("expected 3 snapshots, got %d", len(snapshots))
+		}
+		first, retrying, second := snapshots[0][0], snapshots[1][0], snapshots[2][0]
+		if first.Name != "db" || first.Attempt != 1 || first.LastErr != nil || !first.NextWake.IsZero() || first.Elapsed != 100*time.Millisecond {
+			t.Errorf("unexpected first attempt %+v", first)
+		}
+		if !errors.Is(retrying.LastErr, errTest) || retrying.Attempt != 1 {
+			t.Errorf("unexpected retrying state %+v", retrying)
+		}
+		if second.Attempt != 2 || !errors.Is(second.LastErr, errTest) || !second.NextWake.IsZero() || second.Elapsed != 1200*time.Millisecond {
+			t.Errorf("unexpected second attempt %+v", second)
+		}
+
+		if got := p.InFlight(); len(got) != 0 {
+			t.Fatalf("expected finished calls to be removed, got %+v", got)
+		}
+	})
+
+	t.Run("reports the wake-up time while sleeping", func(t *testing.T) {
+		clock := newBlockingClock()
+		p := retry.New(retry.WithBackoff(retry.Constant(time.Hour)), retry.WithClock(clock))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- p.Do(ctx, func(context.Context) error { return errTest }, retry.WithTracking("slow"))
+		}()
+		<-clock.sleeping
+
+		calls := p.InFlight()
+		if len(calls) != 1 || calls[0].Name != "slow" || calls[0].NextWake.Sub(calls[0].Started) < time.Hour {
+			t.Errorf("unexpected calls %+v", calls)
+		}
+
+		cancel()
+		<-done
+		if got := p.InFlight(); len(got) != 0 {
+			t.Fatalf("expected no calls, got %+v", got)
+		}
+	})
+
+	t.Run("reports paused calls", func(t *testing.T) {
+		clock := newBlockingClock()
+		p := retry.New(retry.WithTracking("db"), retry.WithBackoff(retry.Constant(0)), retry.WithClock(clock))
+		p.SetOverrides(retry.Overrides{Paused: true})
+
+		done := make(chan error)
+		go func() {
+			done <- p.Do(context.Background(), func(context.Context) error { return errTest })
+		}()
+		<-clock.sleeping
+
+		if calls := p.InFlight(); len(calls) != 1 || !calls[0].Paused {
+			t.Fatalf("expected a paused call, got %+v", calls)
+		}
+
+		p.SetOverrides(retry.Overrides{MaxAttempts: 1})
+		if err := <-done; !errors.Is(err, errTest) {
+			t.Fatalf("expected errTest, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,301 @@
+package retry
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JSONSchema is a JSON Schema (draft 2020-12) document describing the JSON
+// form of a Policy, for validating configuration files.
+//
+// A policy is encoded as:
+//
+//	{
+//	  "maxAttempts": 5,
+//	  "maxDuration": "30s",
+//	  "backoff": {
+//	    "type": "jitter", "factor": 0.2,
+//	    "backoff": {
+//	      "type": "cap", "max": "10s",
+//	      "backoff": {"type": "exponential", "base": "100ms"}
+//	    }
+//	  }
+//	}
+//
+// Durations are Go duration strings. Each backoff is an object whose "type"
+// is one of constant, linear, exponential, cap, min or jitter; wrappers nest
+// the backoff they wrap under "backoff". Only the budget and backoff are
+// encoded: hooks, conditions, middleware and the clock are code, not config.
+//
+//go:embed policy.schema.json
+var JSONSchema string
+
+// policyJSON is the JSON form of a Policy.
+type policyJSON struct {
+	MaxAttempts *int            `json:"maxAttempts,omitempty"`
+	MaxDuration *duration       `json:"maxDuration,omitempty"`
+	Backoff     json.RawMessage `json:"backoff,omitempty"`
+}
+
+// MarshalJSON encodes the policy's budget and backoff. It fails if the
+// backoff chain contains a strategy other than the built-in ones.
+func (p *Policy) MarshalJSON() ([]byte, error) {
+	d := p.Describe()
+	b, err := marshalBackoff(p.cfg.backoff)
+	if err != nil {
+		return nil, err
+	}
+	v := policyJSON{MaxAttempts: &d.MaxAttempts, Backoff: b}
+	if d.MaxDuration > 0 {
+		md := duration(d.MaxDuration)
+		v.MaxDuration = &md
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a policy's budget and backoff. Fields that are absent
+// keep their current value, or New's default when p is the zero Policy.
+// Hooks and other call-level options already set on p are kept.
+// The result is validated, and any problems are returned as errors.
+//
+// UnmarshalJSON modifies p, so it must not be used on a policy that is
+// already shared with other goroutines.
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	var v policyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	cfg := p.cfg
+	if cfg.clock == nil && cfg.backoff == nil {
+		cfg = New().cfg
+	}
+	if v.MaxAttempts != nil {
+		cfg.maxAttempts = *v.MaxAttempts
+	}
+	if v.MaxDuration != nil {
+		cfg.maxDuration = time.Duration(*v.MaxDuration)
+	}
+	if v.Backoff != nil {
+		b, err := UnmarshalBackoff(v.Backoff)
+		if err != nil {
+			return err
+		}
+		cfg.backoff = b
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	p.cfg = cfg
+	if p.ctl == nil {
+		p.ctl = newControl()
+	}
+	return nil
+}
+
+// backoffJSON is the JSON form of every built-in Backoff.
+type backoffJSON struct {
+	Type    string          `json:"type"`
+	Delay   *duration       `json:"delay,omitempty"`
+	Base    *duration       `json:"base,omitempty"`
+	Max     *duration       `json:"max,omitempty"`
+	Min     *duration       `json:"min,omitempty"`
+	Factor  *float64        `json:"factor,omitempty"`
+	Backoff json.RawMessage `json:"backoff,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b ConstantBackoff) MarshalJSON() ([]byte, error) {
+	d := duration(b.d)
+	return json.Marshal(backoffJSON{Type: "constant", Delay: &d})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *ConstantBackoff) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b LinearBackoff) MarshalJSON() ([]byte, error) {
+	d := duration(b.base)
+	return json.Marshal(backoffJSON{Type: "linear", Base: &d})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *LinearBackoff) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b ExponentialBackoff) MarshalJSON() ([]byte, error) {
+	d := duration(b.base)
+	return json.Marshal(backoffJSON{Type: "exponential", Base: &d})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *ExponentialBackoff) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b CapBackoff) MarshalJSON() ([]byte, error) {
+	inner, err := marshalBackoff(b.b)
+	if err != nil {
+		return nil, err
+	}
+	d := duration(b.max)
+	return json.Marshal(backoffJSON{Type: "cap", Max: &d, Backoff: inner})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *CapBackoff) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b MinBackoff) MarshalJSON() ([]byte, error) {
+	inner, err := marshalBackoff(b.b)
+	if err != nil {
+		return nil, err
+	}
+	d := duration(b.min)
+	return json.Marshal(backoffJSON{Type: "min", Min: &d, Backoff: inner})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *MinBackoff) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b JitterBackoff) MarshalJSON() ([]byte, error) {
+	inner, err := marshalBackoff(b.b)
+	if err != nil {
+		return nil, err
+	}
+	f := b.factor
+	return json.Marshal(backoffJSON{Type: "jitter", Factor: &f, Backoff: inner})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *JitterBackoff) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, b)
+}
+
+// UnmarshalBackoff decodes a backoff chain from its JSON form, choosing the
+// concrete type from each object's "type" field. Use it to decode a backoff
+// into a Backoff interface value.
+func UnmarshalBackoff(data []byte) (Backoff, error) {
+	var v backoffJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	need := func(field string, present bool) error {
+		if !present {
+			return fmt.Errorf("retry: %s backoff requires %q", v.Type, field)
+		}
+		return nil
+	}
+	wrapped := func() (Backoff, error) {
+		if err := need("backoff", v.Backoff != nil); err != nil {
+			return nil, err
+		}
+		return UnmarshalBackoff(v.Backoff)
+	}
+
+	switch v.Type {
+	case "constant":
+		if err := need("delay", v.Delay != nil); err != nil {
+			return nil, err
+		}
+		return Constant(time.Duration(*v.Delay)), nil
+	case "linear":
+		if err := need("base", v.Base != nil); err != nil {
+			return nil, err
+		}
+		return Linear(time.Duration(*v.Base)), nil
+	case "exponential":
+		if err := need("base", v.Base != nil); err != nil {
+			return nil, err
+		}
+		return Exponential(time.Duration(*v.Base)), nil
+	case "cap":
+		if err := need("max", v.Max != nil); err != nil {
+			return nil, err
+		}
+		inner, err := wrapped()
+		if err != nil {
+			return nil, err
+		}
+		return WithCap(time.Duration(*v.Max), inner), nil
+	case "min":
+		if err := need("min", v.Min != nil); err != nil {
+			return nil, err
+		}
+		inner, err := wrapped()
+		if err != nil {
+			return nil, err
+		}
+		return WithMin(time.Duration(*v.Min), inner), nil
+	case "jitter":
+		if err := need("factor", v.Factor != nil); err != nil {
+			return nil, err
+		}
+		inner, err := wrapped()
+		if err != nil {
+			return nil, err
+		}
+		return WithJitter(*v.Factor, inner), nil
+	case "":
+		return nil, errors.New(`retry: backoff requires "type"`)
+	default:
+		return nil, fmt.Errorf("retry: unknown backoff type %q", v.Type)
+	}
+}
+
+// unmarshalInto decodes data into the concrete backoff pointed to by dst,
+// failing if the encoded type does not match.
+func unmarshalInto[T Backoff](data []byte, dst *T) error {
+	b, err := UnmarshalBackoff(data)
+	if err != nil {
+		return err
+	}
+	v, ok := b.(T)
+	if !ok {
+		return fmt.Errorf("retry: cannot decode %s into %T", backoffString(b), *dst)
+	}
+	*dst = v
+	return nil
+}
+
+// marshalBackoff encodes b, which must be one of the built-in strategies.
+func marshalBackoff(b Backoff) (json.RawMessage, error) {
+	if _, ok := b.(json.Marshaler); !ok {
+		return nil, fmt.Errorf("retry: cannot marshal %s backoff", backoffString(b))
+	}
+	return json.Marshal(b)
+}
+
+// duration encodes a time.Duration as a Go duration string.
+type duration time.Duration
+
+func (d duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("retry: duration must be a string such as \"100ms\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("retry: %w", err)
+	}
+	*d = duration(v)
+	return nil
+}
@@ -0,0 +1,189 @@
+package retry_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestBackoffJSON(t *testing.T) {
+	cases := []struct {
+		backoff retry.Backoff
+		json    string
+	}{
+		{retry.Constant(100 * time.Millisecond), `{"type":"constant","delay":"100ms"}`},
+		{retry.Linear(time.Second), `{"type":"linear","base":"1s"}`},
+		{retry.Exponential(100 * time.Millisecond), `{"type":"exponential","base":"100ms"}`},
+		{
+			retry.WithCap(10*time.Second, retry.Exponential(100*time.Millisecond)),
+			`{"type":"cap","max":"10s","backoff":{"type":"exponential","base":"100ms"}}`,
+		},
+		{
+			retry.WithMin(time.Second, retry.Linear(100*time.Millisecond)),
+			`{"type":"min","min":"1s","backoff":{"type":"linear","base":"100ms"}}`,
+		},
+		{
+			retry.WithJitter(0.2, retry.WithCap(10*time.Second, retry.Exponential(100*time.Millisecond))),
+			`{"type":"jitter","factor":0.2,"backoff":{"type":"cap","max":"10s","backoff":{"type":"exponential","base":"100ms"}}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.json, func(t *testing.T) {
+			data, err := json.Marshal(tc.backoff)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Fatalf("expected %s, got %s", tc.json, data)
+			}
+
+			b, err := retry.UnmarshalBackoff(data)
+			if err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if b != tc.backoff {
+				t.Fatalf("expected round trip to %v, got %v", tc.backoff, b)
+			}
+		})
+	}
+}
+
+func TestBackoffUnmarshalJSON(t *testing.T) {
+	t.Run("into concrete type", func(t *testing.T) {
+		var b retry.CapBackoff
+		err := json.Unmarshal([]byte(`{"type":"cap","max":"1s","backoff":{"type":"constant","delay":"2s"}}`), &b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.Max() != time.Second || b.Delay(1) != time.Second {
+			t.Fatalf("unexpected backoff %v", b)
+		}
+	})
+
+	t.Run("into mismatched type", func(t *testing.T) {
+		var b retry.ConstantBackoff
+		err := json.Unmarshal([]byte(`{"type":"linear","base":"1s"}`), &b)
+		if err == nil || !strings.Contains(err.Error(), "cannot decode linear(1s)") {
+			t.Fatalf("expected type mismatch error, got %v", err)
+		}
+	})
+
+	errCases := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"missing type", `{"delay":"1s"}`, `backoff requires "type"`},
+		{"unknown type", `{"type":"fibonacci"}`, `unknown backoff type "fibonacci"`},
+		{"missing field", `{"type":"constant"}`, `constant backoff requires "delay"`},
+		{"missing wrapped", `{"type":"jitter","factor":0.1}`, `jitter backoff requires "backoff"`},
+		{"numeric duration", `{"type":"constant","delay":100}`, "duration must be a string"},
+		{"bad duration", `{"type":"constant","delay":"soon"}`, `invalid duration "soon"`},
+		{"bad nested", `{"type":"cap","max":"1s","backoff":{"type":"nope"}}`, `unknown backoff type "nope"`},
+	}
+	for _, tc := range errCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := retry.UnmarshalBackoff([]byte(tc.json))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestPolicyJSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		p := retry.New(
+			retry.WithMaxAttempts(5),
+			retry.WithMaxDuration(30*time.Second),
+			retry.WithBackoff(retry.WithJitter(0.2, retry.WithCap(10*time.Second, retry.Exponential(100*time.Millisecond)))),
+		)
+
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		want := `{"maxAttempts":5,"maxDuration":"30s","backoff":{"type":"jitter","factor":0.2,"backoff":{"type":"cap","max":"10s","backoff":{"type":"exponential","base":"100ms"}}}}`
+		if string(data) != want {
+			t.Fatalf("expected %s, got %s", want, data)
+		}
+
+		var decoded retry.Policy
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if decoded.String() != p.String() {
+			t.Fatalf("expected %s, got %s", p, &decoded)
+		}
+	})
+
+	t.Run("zero policy gets defaults", func(t *testing.T) {
+		var p retry.Policy
+		if err := json.Unmarshal([]byte(`{"maxAttempts":2}`), &p); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if got := p.String(); got != "attempts=2 backoff=exponential(100ms)" {
+			t.Fatalf("unexpected policy %s", got)
+		}
+
+		attempts := 0
+		_ = p.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errTest
+		}, retry.WithClock(newFakeClock()))
+		if attempts != 2 {
+			t.Fatalf("expected 2 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("keeps existing hooks", func(t *testing.T) {
+		retries := 0
+		p := retry.New(
+			retry.WithClock(newFakeClock()),
+			retry.OnRetry(func(context.Context, int, error, time.Duration) { retries++ }),
+		)
+		if err := json.Unmarshal([]byte(`{"maxAttempts":4}`), p); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+
+		_ = p.Do(context.Background(), func(ctx context.Context) error { return errTest })
+		if retries != 3 {
+			t.Fatalf("expected 3 retries, got %d", retries)
+		}
+	})
+
+	t.Run("rejects invalid policy", func(t *testing.T) {
+		var p retry.Policy
+		err := json.Unmarshal([]byte(`{"backoff":{"type":"jitter","factor":3,"backoff":{"type":"constant","delay":"1s"}}}`), &p)
+		if err == nil || !strings.Contains(err.Error(), "jitter factor 3 is outside [0, 1]") {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("cannot marshal custom backoff", func(t *testing.T) {
+		p := retry.New(retry.WithBackoff(retry.WithCap(time.Second, retry.BackoffFunc(func(int) time.Duration {
+			return 0
+		}))))
+		_, err := json.Marshal(p)
+		if err == nil || !strings.Contains(err.Error(), "cannot marshal custom backoff") {
+			t.Fatalf("expected custom backoff error, got %v", err)
+		}
+	})
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(retry.JSONSchema), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	for _, typ := range []string{"constant", "linear", "exponential", "cap", "min", "jitter"} {
+		if !strings.Contains(retry.JSONSchema, `"`+typ+`"`) {
+			t.Errorf("schema does not mention backoff type %q", typ)
+		}
+	}
+}
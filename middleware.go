@@ -0,0 +1,40 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Middleware wraps a single attempt. It receives the next Func in the chain
+// and returns a Func that calls it, adding behavior before or after.
+type Middleware func(next Func) Func
+
+// AttemptInfo describes the attempt being executed.
+type AttemptInfo struct {
+	// Attempt is the 1-based attempt number.
+	Attempt int
+	// MaxAttempts is the attempt budget for the loop.
+	MaxAttempts int
+	// Elapsed is the time since the first attempt started.
+	Elapsed time.Duration
+	// LastErr is the error returned by the previous attempt, or nil on the first.
+	LastErr error
+}
+
+// attemptKey is the context key for AttemptInfo.
+type attemptKey struct{}
+
+// AttemptFromContext returns information about the current attempt.
+// It is available to middleware and to fn when WithMiddleware is used.
+func AttemptFromContext(ctx context.Context) (AttemptInfo, bool) {
+	info, ok := ctx.Value(attemptKey{}).(AttemptInfo)
+	return info, ok
+}
+
+// chain wraps fn with middleware so that mw[0] is the outermost layer.
+func chain(fn Func, mw []Middleware) Func {
+	for i := len(mw) - 1; i >= 0; i-- {
+		fn = mw[i](fn)
+	}
+	return fn
+}
@@ -0,0 +1,108 @@
+package retry
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Operation identifies what a call is retrying, as set by WithName and
+// WithLabels.
+type Operation struct {
+	// Name names the operation, such as "db.query".
+	Name string
+	// Labels are extra dimensions, such as the target region. The map is
+	// shared and must not be modified.
+	Labels map[string]string
+}
+
+// WithName names the operation being retried. The name is available to hooks,
+// middleware and fn through OperationFromContext, is included in the error
+// returned when the call fails, and is used by the logging, metrics, tracing
+// and tracking integrations.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithLabels adds labels describing the operation, such as
+// {"region": "us-east-1"}. Like WithName, they reach hooks and integrations
+// and are included in the returned error. Labels from later options are
+// merged over earlier ones.
+func WithLabels(labels map[string]string) Option {
+	return func(c *config) {
+		merged := make(map[string]string, len(c.labels)+len(labels))
+		maps.Copy(merged, c.labels)
+		maps.Copy(merged, labels)
+		c.labels = merged
+	}
+}
+
+// operationKey is the context key for Operation.
+type operationKey struct{}
+
+// OperationFromContext returns the operation of the call running ctx. It is
+// available to hooks, middleware and fn when WithName or WithLabels is used.
+func OperationFromContext(ctx context.Context) (Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(Operation)
+	return op, ok
+}
+
+// OperationError is the error returned by a failed call made with WithName
+// or WithLabels. It wraps the error the call would otherwise return.
+type OperationError struct {
+	Operation
+	// Attempts is the number of attempts made.
+	Attempts int
+	// Reason is why the retry loop stopped.
+	Reason StopReason
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the operation, its labels and the underlying error, as in
+// "db.query [region=us-east-1]: connection refused".
+func (e *OperationError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Operation.String())
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// String returns the name followed by the labels in sorted order, as in
+// "db.query [region=us-east-1 tier=primary]".
+func (o Operation) String() string {
+	var b strings.Builder
+	b.WriteString(o.Name)
+	if len(o.Labels) > 0 {
+		if o.Name != "" {
+			b.WriteByte(' ')
+		}
+		b.WriteByte('[')
+		for i, k := range slices.Sorted(maps.Keys(o.Labels)) {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(k)
+			b.WriteByte('=')
+			b.WriteString(o.Labels[k])
+		}
+		b.WriteByte(']')
+	}
+	return b.String()
+}
+
+// operation returns the call's operation and whether one is set.
+func (c *config) operation() (Operation, bool) {
+	if c.name == "" && len(c.labels) == 0 {
+		return Operation{}, false
+	}
+	return Operation{Name: c.name, Labels: c.labels}, true
+}
@@ -0,0 +1,171 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/bjaus/retry"
+)
+
+func TestOperation(t *testing.T) {
+	t.Run("reaches fn and hooks", func(t *testing.T) {
+		var inFn, inHook retry.Operation
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			inFn, _ = retry.OperationFromContext(ctx)
+			return errTest
+		},
+			retry.WithMaxAttempts(1),
+			retry.WithName("db.query"),
+			retry.WithLabels(map[string]string{"region": "us-east-1"}),
+			retry.OnGiveUp(func(ctx context.Context, _ int, _ error, _ retry.StopReason) {
+				inHook, _ = retry.OperationFromContext(ctx)
+			}),
+		)
+
+		for _, op := range []retry.Operation{inFn, inHook} {
+			if op.Name != "db.query" || op.Labels["region"] != "us-east-1" {
+				t.Fatalf("unexpected operation %+v", op)
+			}
+		}
+	})
+
+	t.Run("absent without a name or labels", func(t *testing.T) {
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			if _, ok := retry.OperationFromContext(ctx); ok {
+				t.Fatal("expected no operation")
+			}
+			return nil
+		})
+	})
+
+	t.Run("labels merge over earlier labels", func(t *testing.T) {
+		base := map[string]string{"region": "us-east-1", "tier": "primary"}
+		policy := retry.New(retry.WithLabels(base))
+
+		var op retry.Operation
+		_ = policy.Do(context.Background(), func(ctx context.Context) error {
+			op, _ = retry.OperationFromContext(ctx)
+			return nil
+		}, retry.WithLabels(map[string]string{"tier": "replica"}))
+
+		if op.String() != "[region=us-east-1 tier=replica]" {
+			t.Fatalf("unexpected operation %q", op)
+		}
+		if base["tier"] != "primary" {
+			t.Fatal("expected the caller's map to be left alone")
+		}
+	})
+
+	t.Run("wraps the final error", func(t *testing.T) {
+		err := retry.Do(context.Background(), func(context.Context) error { return errTest },
+			retry.WithMaxAttempts(2),
+			retry.WithClock(newFakeClock()),
+			retry.WithName("db.query"),
+			retry.WithLabels(map[string]string{"tier": "primary", "region": "us-east-1"}),
+		)
+
+		if got, want := err.Error(), "db.query [region=us-east-1 tier=primary]: test error"; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+		var opErr *retry.OperationError
+		if !errors.As(err, &opErr) || !errors.Is(err, errTest) {
+			t.Fatalf("expected an OperationError wrapping errTest, got %v", err)
+		}
+		if opErr.Name != "db.query" || opErr.Attempts != 2 || opErr.Reason != retry.StopExhausted {
+			t.Fatalf("unexpected error fields %+v", opErr)
+		}
+	})
+
+	t.Run("hooks see the wrapped error", func(t *testing.T) {
+		var hookErr error
+		err := retry.Do(context.Background(), func(context.Context) error { return retry.Stop(errTest) },
+			retry.WithName("db.query"),
+			retry.OnGiveUp(func(_ context.Context, _ int, err error, _ retry.StopReason) {
+				hookErr = err
+			}),
+		)
+
+		if hookErr != err || err.Error() != "db.query: test error" {
+			t.Fatalf("unexpected errors %v, %v", hookErr, err)
+		}
+	})
+
+	t.Run("wraps context errors", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := retry.Do(ctx, func(context.Context) error { return errTest },
+			retry.WithName("db.query"),
+		)
+
+		var opErr *retry.OperationError
+		if !errors.As(err, &opErr) || opErr.Reason != retry.StopContext {
+			t.Fatalf("expected a context OperationError, got %v", err)
+		}
+	})
+
+	t.Run("success is not wrapped", func(t *testing.T) {
+		if err := retry.Do(context.Background(), func(context.Context) error { return nil },
+			retry.WithName("db.query"),
+		); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("names tracked calls", func(t *testing.T) {
+		policy := retry.New(retry.WithTracking(""), retry.WithName("db.query"))
+		var calls []retry.InFlightCall
+		_ = policy.Do(context.Background(), func(context.Context) error {
+			calls = policy.InFlight()
+			return nil
+		})
+
+		if len(calls) != 1 || calls[0].Name != "db.query" {
+			t.Fatalf("unexpected in-flight calls %+v", calls)
+		}
+	})
+
+	t.Run("labels profiles", func(t *testing.T) {
+		var label string
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			label, _ = pprof.Label(ctx, retry.LabelOperation)
+			return nil
+		}, retry.WithRuntimeTrace(""), retry.WithName("db.query"))
+
+		if label != "db.query" {
+			t.Fatalf("unexpected pprof label %q", label)
+		}
+	})
+
+	t.Run("annotates the call span", func(t *testing.T) {
+		tracer := &recordingTracer{}
+		_ = retry.Do(context.Background(), func(context.Context) error { return nil },
+			retry.WithTracer(tracer),
+			retry.WithName("db.query"),
+			retry.WithLabels(map[string]string{"region": "us-east-1"}),
+		)
+
+		attrs := tracer.spans[0].attrs
+		if attrs[retry.AttrOperation] != "db.query" || attrs[retry.AttrLabelPrefix+"region"] != "us-east-1" {
+			t.Fatalf("unexpected attributes %v", attrs)
+		}
+	})
+}
+
+func TestOperationString(t *testing.T) {
+	tests := []struct {
+		op   retry.Operation
+		want string
+	}{
+		{retry.Operation{}, ""},
+		{retry.Operation{Name: "db"}, "db"},
+		{retry.Operation{Labels: map[string]string{"b": "2", "a": "1"}}, "[a=1 b=2]"},
+		{retry.Operation{Name: "db", Labels: map[string]string{"a": "1"}}, "db [a=1]"},
+	}
+	for _, tt := range tests {
+		if got := tt.op.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
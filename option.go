@@ -1,6 +1,9 @@
 package retry
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // config holds all retry configuration.
 type config struct {
@@ -11,16 +14,44 @@ type config struct {
 	clock       Clock
 
 	// Call-level options
-	condition   Condition
-	onRetry     OnRetryFunc
-	onSuccess   OnSuccessFunc
-	onExhausted OnExhaustedFunc
-	allErrors   bool
+	condition    Condition
+	hooks        hooks
+	allErrors    bool
+	remedies     []remedy
+	middleware   []Middleware
+	tracked      bool
+	trackName    string
+	tracer       Tracer
+	runtimeTrace bool
+	traceOp      string
+	name         string
+	labels       map[string]string
+}
+
+// clip returns a copy of c whose slices have no spare capacity, so options
+// applied to copies of the result append to fresh arrays instead of sharing
+// (and racing on) the original backing arrays.
+func (c config) clip() config {
+	c.remedies = slices.Clip(c.remedies)
+	c.middleware = slices.Clip(c.middleware)
+	c.hooks = c.hooks.clip()
+	return c
 }
 
 // Option configures retry behavior.
 type Option func(*config)
 
+// Options combines several options into one, applied in order. It lets
+// integration packages offer a single option that installs hooks and
+// middleware together.
+func Options(opts ...Option) Option {
+	return func(c *config) {
+		for _, opt := range opts {
+			opt(c)
+		}
+	}
+}
+
 // WithMaxAttempts sets the maximum number of attempts.
 func WithMaxAttempts(n int) Option {
 	return func(c *config) {
@@ -71,24 +102,89 @@ func Not(cond Condition) Condition {
 	}
 }
 
-// OnRetry sets a hook that is called before each retry sleep.
+// OnRetry adds a hook that is called before each retry sleep.
 func OnRetry(fn OnRetryFunc) Option {
 	return func(c *config) {
-		c.onRetry = fn
+		c.hooks.onRetry = append(c.hooks.onRetry, fn)
 	}
 }
 
-// OnSuccess sets a hook that is called when the function succeeds.
+// OnSuccess adds a hook that is called when the function succeeds.
 func OnSuccess(fn OnSuccessFunc) Option {
 	return func(c *config) {
-		c.onSuccess = fn
+		c.hooks.onSuccess = append(c.hooks.onSuccess, fn)
 	}
 }
 
-// OnExhausted sets a hook that is called when all retry attempts are exhausted.
+// OnExhausted adds a hook that is called when all retry attempts are exhausted.
 func OnExhausted(fn OnExhaustedFunc) Option {
 	return func(c *config) {
-		c.onExhausted = fn
+		c.hooks.onExhausted = append(c.hooks.onExhausted, fn)
+	}
+}
+
+// OnGiveUp adds a hook that is called whenever the retry loop stops without
+// succeeding, including when If rejects an error or Stop is returned.
+// The reason reports which path ended the loop.
+func OnGiveUp(fn OnGiveUpFunc) Option {
+	return func(c *config) {
+		c.hooks.onGiveUp = append(c.hooks.onGiveUp, fn)
+	}
+}
+
+// OnAttemptStart adds a hook that is called before each attempt, including the first.
+func OnAttemptStart(fn OnAttemptStartFunc) Option {
+	return func(c *config) {
+		c.hooks.onAttemptStart = append(c.hooks.onAttemptStart, fn)
+	}
+}
+
+// OnAttemptEnd adds a hook that is called after each attempt with its error
+// and duration, as measured by the configured Clock.
+func OnAttemptEnd(fn OnAttemptEndFunc) Option {
+	return func(c *config) {
+		c.hooks.onAttemptEnd = append(c.hooks.onAttemptEnd, fn)
+	}
+}
+
+// OnHookPanic adds a hook that is called when another hook panics, with the
+// recovered value. Panicking hooks are recovered so they cannot crash the
+// retry loop; use OnHookPanic to log or count them instead of losing them
+// silently.
+func OnHookPanic(fn OnHookPanicFunc) Option {
+	return func(c *config) {
+		c.hooks.onHookPanic = append(c.hooks.onHookPanic, fn)
+	}
+}
+
+// ReplaceHooks discards every hook added by earlier options, such as those
+// set on a policy, so that only hooks added after it run.
+// Remediations registered with OnError are not affected.
+func ReplaceHooks() Option {
+	return func(c *config) {
+		c.hooks = hooks{}
+	}
+}
+
+// BeforeRetry adds a hook that is called before each retry sleep and can
+// cancel the retry, replace its delay, or mark the error terminal.
+// It runs before OnRetry, which observes the final delay. When several
+// BeforeRetry hooks are registered, each sees the delay chosen by the previous
+// one and the first to cancel or terminate wins.
+func BeforeRetry(fn BeforeRetryFunc) Option {
+	return func(c *config) {
+		c.hooks.beforeRetry = append(c.hooks.beforeRetry, fn)
+	}
+}
+
+// WithMiddleware wraps every attempt with the given middleware.
+// The first middleware is the outermost: WithMiddleware(a, b) runs
+// a(b(fn)). Middleware from earlier options, such as those set on a policy,
+// wraps middleware from later ones. Inside the chain, AttemptFromContext
+// reports the current attempt.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *config) {
+		c.middleware = append(c.middleware, mw...)
 	}
 }
 
@@ -100,3 +196,55 @@ func WithAllErrors() Option {
 		c.allErrors = true
 	}
 }
+
+// WithTracking records calls in flight under name, or the WithName name if
+// name is empty, so they can be inspected with Policy.InFlight, for example
+// from a /debug/retries page. Tracking is off by default and costs nothing
+// until enabled.
+func WithTracking(name string) Option {
+	return func(c *config) {
+		c.tracked = true
+		c.trackName = name
+	}
+}
+
+// remedy is a remediation registered with OnError.
+type remedy struct {
+	cond          Condition
+	fn            RemedyFunc
+	skipDelay     bool
+	stopOnFailure bool
+}
+
+// RemedyOption configures a remediation registered with OnError.
+type RemedyOption func(*remedy)
+
+// SkipDelay retries immediately, without the backoff sleep, when the
+// remediation succeeds.
+func SkipDelay() RemedyOption {
+	return func(r *remedy) {
+		r.skipDelay = true
+	}
+}
+
+// StopOnFailure stops the retry loop when the remediation itself fails.
+// The returned error joins the attempt error with the remediation error.
+// By default, a failed remediation is ignored and the loop retries normally.
+func StopOnFailure() RemedyOption {
+	return func(r *remedy) {
+		r.stopOnFailure = true
+	}
+}
+
+// OnError registers a remediation that runs between attempts when cond
+// matches the error, before the backoff sleep. A nil cond matches every error.
+// Multiple remediations run in the order they were registered.
+func OnError(cond Condition, fn RemedyFunc, opts ...RemedyOption) Option {
+	r := remedy{cond: cond, fn: fn}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return func(c *config) {
+		c.remedies = append(c.remedies, r)
+	}
+}
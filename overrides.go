@@ -0,0 +1,150 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Overrides are runtime adjustments to a live policy, such as an incident
+// kill switch. They apply on top of the policy's configuration to every Do
+// call, including calls already running, from their next retry on. The zero
+// value changes nothing.
+//
+// Overrides belong to one Policy value: policies derived with With start
+// without any.
+type Overrides struct {
+	// MaxAttempts, if positive, lowers the attempt limit. 1 disables retries.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// DelayScale, if positive, multiplies every backoff delay. Values above 1
+	// throttle retries.
+	DelayScale float64 `json:"delayScale,omitempty"`
+
+	// Paused holds retries until the policy is resumed, the context is done or
+	// the time budget runs out. First attempts still run.
+	Paused bool `json:"paused,omitempty"`
+}
+
+// control holds a policy's runtime state: its overrides, tracked calls and
+// shutdown state. Waiters are woken by closing the changed channel of the
+// state being replaced.
+type control struct {
+	state   atomic.Pointer[controlState]
+	flights tracker
+	life    lifecycle
+}
+
+func newControl() *control {
+	c := &control{}
+	c.life.init()
+	return c
+}
+
+type controlState struct {
+	Overrides
+	changed chan struct{}
+}
+
+// noOverrides is the state of a policy that has never been overridden.
+var noOverrides = &controlState{}
+
+func (c *control) load() *controlState {
+	if c == nil {
+		return noOverrides
+	}
+	if s := c.state.Load(); s != nil {
+		return s
+	}
+	return noOverrides
+}
+
+func (c *control) store(o Overrides) {
+	old := c.state.Swap(&controlState{Overrides: o, changed: make(chan struct{})})
+	if old != nil {
+		close(old.changed)
+	}
+}
+
+// Overrides returns the runtime overrides currently applied to the policy.
+func (p *Policy) Overrides() Overrides {
+	return p.ctl.load().Overrides
+}
+
+// SetOverrides atomically replaces the policy's runtime overrides. Pass the
+// zero Overrides to clear them; clearing Paused wakes held retries. It has no
+// effect on the zero Policy.
+func (p *Policy) SetOverrides(o Overrides) {
+	if p.ctl == nil {
+		return
+	}
+	p.ctl.store(o)
+}
+
+// limit applies the max attempts override to maxAttempts.
+func (s *controlState) limit(maxAttempts int) int {
+	if s.MaxAttempts > 0 {
+		return min(maxAttempts, s.MaxAttempts)
+	}
+	return maxAttempts
+}
+
+// scale applies the delay scale override to d.
+func (s *controlState) scale(d time.Duration) time.Duration {
+	if s.DelayScale <= 0 {
+		return d
+	}
+	scaled := float64(d) * s.DelayScale
+	if scaled >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(scaled)
+}
+
+// waitWhilePaused blocks while the policy is paused. It returns true if the
+// wait ended because the time budget ran out (deadline is zero for none),
+// the context's error if it was canceled, and ErrShuttingDown if the policy
+// was shut down.
+func (c *control) waitWhilePaused(ctx context.Context, clock Clock, deadline time.Time, f *flight) (expired bool, err error) {
+	for {
+		s := c.load()
+		if !s.Paused {
+			return false, nil
+		}
+		f.paused()
+
+		wait := time.Duration(math.MaxInt64)
+		if !deadline.IsZero() {
+			wait = deadline.Sub(clock.Now())
+			if wait <= 0 {
+				return true, nil
+			}
+		}
+
+		waitCtx, cancel := context.WithCancel(ctx)
+		stop := make(chan struct{})
+		go func() {
+			select {
+			case <-s.changed:
+				cancel()
+			case <-c.life.down.Done():
+				cancel()
+			case <-stop:
+			}
+		}()
+		serr := clock.Sleep(waitCtx, wait)
+		close(stop)
+		cancel()
+
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if c.life.shuttingDown() {
+			return false, ErrShuttingDown
+		}
+		if serr == nil && !deadline.IsZero() {
+			return true, nil
+		}
+	}
+}
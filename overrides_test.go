@@ -0,0 +1,178 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestOverrides(t *testing.T) {
+	t.Run("max attempts disables retries", func(t *testing.T) {
+		p := retry.New(retry.WithMaxAttempts(5), retry.WithClock(newFakeClock()))
+		p.SetOverrides(retry.Overrides{MaxAttempts: 1})
+
+		calls := 0
+		var reason retry.StopReason
+		_ = p.Do(context.Background(), func(context.Context) error {
+			calls++
+			return errTest
+		}, retry.OnGiveUp(func(_ context.Context, _ int, _ error, r retry.StopReason) { reason = r }))
+		if calls != 1 || reason != retry.StopExhausted {
+			t.Fatalf("expected 1 call exhausted, got %d (%v)", calls, reason)
+		}
+
+		p.SetOverrides(retry.Overrides{})
+		calls = 0
+		_ = p.Do(context.Background(), func(context.Context) error {
+			calls++
+			return errTest
+		})
+		if calls != 5 {
+			t.Fatalf("expected 5 calls after clearing, got %d", calls)
+		}
+	})
+
+	t.Run("max attempts only lowers the limit", func(t *testing.T) {
+		p := retry.New(retry.WithMaxAttempts(2), retry.WithClock(newFakeClock()))
+		p.SetOverrides(retry.Overrides{MaxAttempts: 10})
+
+		calls := 0
+		_ = p.Do(context.Background(), func(context.Context) error {
+			calls++
+			return errTest
+		})
+		if calls != 2 {
+			t.Fatalf("expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("applies to running calls", func(t *testing.T) {
+		p := retry.New(retry.WithMaxAttempts(5), retry.WithClock(newFakeClock()))
+
+		calls := 0
+		_ = p.Do(context.Background(), func(context.Context) error {
+			calls++
+			if calls == 2 {
+				p.SetOverrides(retry.Overrides{MaxAttempts: 1})
+			}
+			return errTest
+		})
+		if calls != 2 {
+			t.Fatalf("expected the kill switch to stop after 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("scales delays", func(t *testing.T) {
+		clock := newFakeClock()
+		p := retry.New(
+			retry.WithMaxAttempts(3),
+			retry.WithBackoff(retry.Constant(100*time.Millisecond)),
+			retry.WithClock(clock),
+		)
+		p.SetOverrides(retry.Overrides{DelayScale: 2.5})
+
+		_ = p.Do(context.Background(), func(context.Context) error { return errTest })
+		if len(clock.sleeps) != 2 || clock.sleeps[0] != 250*time.Millisecond || clock.sleeps[1] != 250*time.Millisecond {
+			t.Fatalf("unexpected sleeps %v", clock.sleeps)
+		}
+	})
+
+	t.Run("scaled delays respect the time budget", func(t *testing.T) {
+		clock := newFakeClock()
+		p := retry.New(
+			retry.WithMaxAttempts(2),
+			retry.WithMaxDuration(time.Second),
+			retry.WithBackoff(retry.Constant(100*time.Millisecond)),
+			retry.WithClock(clock),
+		)
+		p.SetOverrides(retry.Overrides{DelayScale: 1e12})
+
+		_ = p.Do(context.Background(), func(context.Context) error { return errTest })
+		if len(clock.sleeps) != 1 || clock.sleeps[0] != time.Second {
+			t.Fatalf("unexpected sleeps %v", clock.sleeps)
+		}
+	})
+
+	t.Run("pause holds retries until resumed", func(t *testing.T) {
+		p := retry.New(retry.WithMaxAttempts(2), retry.WithBackoff(retry.Constant(0)))
+		p.SetOverrides(retry.Overrides{Paused: true})
+
+		first := make(chan struct{})
+		done := make(chan error)
+		calls := 0
+		go func() {
+			done <- p.Do(context.Background(), func(context.Context) error {
+				calls++
+				if calls == 1 {
+					close(first)
+					return errTest
+				}
+				return nil
+			})
+		}()
+
+		<-first
+		select {
+		case <-done:
+			t.Fatal("expected the retry to be held")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		p.SetOverrides(retry.Overrides{})
+		if err := <-done; err != nil {
+			t.Fatalf("expected success after resuming, got %v", err)
+		}
+		if calls != 2 {
+			t.Fatalf("expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("pause ends with the context", func(t *testing.T) {
+		p := retry.New(retry.WithBackoff(retry.Constant(0)))
+		p.SetOverrides(retry.Overrides{Paused: true})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var reason retry.StopReason
+		err := p.Do(ctx, func(context.Context) error {
+			cancel()
+			return errTest
+		}, retry.OnGiveUp(func(_ context.Context, _ int, _ error, r retry.StopReason) { reason = r }))
+		if !errors.Is(err, errTest) || reason != retry.StopContext {
+			t.Fatalf("expected errTest with StopContext, got %v (%v)", err, reason)
+		}
+	})
+
+	t.Run("pause ends with the time budget", func(t *testing.T) {
+		p := retry.New(
+			retry.WithMaxDuration(time.Second),
+			retry.WithBackoff(retry.Constant(0)),
+			retry.WithClock(newFakeClock()),
+		)
+		p.SetOverrides(retry.Overrides{Paused: true})
+
+		calls := 0
+		var reason retry.StopReason
+		_ = p.Do(context.Background(), func(context.Context) error {
+			calls++
+			return errTest
+		}, retry.OnGiveUp(func(_ context.Context, _ int, _ error, r retry.StopReason) { reason = r }))
+		if calls != 1 || reason != retry.StopMaxDuration {
+			t.Fatalf("expected 1 call with StopMaxDuration, got %d (%v)", calls, reason)
+		}
+	})
+
+	t.Run("derived policies start without overrides", func(t *testing.T) {
+		p := retry.New()
+		p.SetOverrides(retry.Overrides{MaxAttempts: 1, Paused: true})
+
+		if got := p.Overrides(); got != (retry.Overrides{MaxAttempts: 1, Paused: true}) {
+			t.Fatalf("unexpected overrides %+v", got)
+		}
+		if got := p.With().Overrides(); got != (retry.Overrides{}) {
+			t.Fatalf("expected no overrides, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,351 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyntaxError reports a malformed policy or backoff definition.
+type SyntaxError struct {
+	// Input is the text being parsed.
+	Input string
+	// Offset is the byte offset in Input where the problem was found.
+	Offset int
+	// Msg describes the problem.
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("retry: syntax error at offset %d in %q: %s", e.Offset, e.Input, e.Msg)
+}
+
+// ParseBackoff parses a backoff definition such as
+//
+//	jitter(0.2, cap(10s, exponential(100ms)))
+//
+// The grammar is:
+//
+//	backoff  = base | wrapper
+//	base     = ("constant" | "linear" | "exponential") "(" duration ")"
+//	wrapper  = ("cap" | "min") "(" duration "," backoff ")"
+//	         | "jitter" "(" factor "," backoff ")"
+//
+// Durations use Go syntax (100ms, 1m30s). The short names const, lin and exp
+// are accepted as aliases. Whitespace between tokens is ignored. The String
+// method of every built-in backoff produces text in this syntax.
+//
+// Syntax errors are returned as *SyntaxError; a well-formed but invalid
+// backoff, such as a jitter factor above 1, is reported as *ConfigError.
+func ParseBackoff(s string) (Backoff, error) {
+	p := &parser{input: s}
+	b, err := p.backoff()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+	if err := validateParsed(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ParsePolicy parses a one-line policy definition such as
+//
+//	attempts=5 duration=30s backoff=jitter(0.2, cap(10s, exp(100ms)))
+//
+// The definition is a space-separated list of key=value fields, each at most
+// once: attempts (an integer), duration (a Go duration) and backoff (see
+// ParseBackoff). Absent fields keep New's defaults. Policy.String produces
+// text in this syntax.
+//
+// Syntax errors are returned as *SyntaxError; the resulting policy is
+// validated, and problems are reported as *ConfigError.
+func ParsePolicy(s string) (*Policy, error) {
+	cfg := New().cfg
+	if err := parsePolicyInto(s, &cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &Policy{cfg: cfg, ctl: newControl()}, nil
+}
+
+// MarshalText encodes the policy's budget and backoff in the syntax accepted
+// by ParsePolicy. It fails if the backoff is not one of the built-in ones.
+func (p *Policy) MarshalText() ([]byte, error) {
+	if err := checkPrintable(p.cfg.backoff); err != nil {
+		return nil, err
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a policy definition in the syntax accepted by
+// ParsePolicy. Like UnmarshalJSON, absent fields keep their current value and
+// call-level options already set on p are kept.
+func (p *Policy) UnmarshalText(text []byte) error {
+	cfg := p.cfg
+	if cfg.clock == nil && cfg.backoff == nil {
+		cfg = New().cfg
+	}
+	if err := parsePolicyInto(string(text), &cfg); err != nil {
+		return err
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	p.cfg = cfg
+	if p.ctl == nil {
+		p.ctl = newControl()
+	}
+	return nil
+}
+
+// parsePolicyInto parses a policy definition and applies its fields to cfg.
+func parsePolicyInto(s string, cfg *config) error {
+	p := &parser{input: s}
+	seen := make(map[string]bool)
+	for {
+		p.skipSpace()
+		if p.done() {
+			return nil
+		}
+		key, at := p.word()
+		if key == "" {
+			return p.errorf(at, "expected field name, found %s", p.peekDesc())
+		}
+		if seen[key] {
+			return p.errorf(at, "duplicate field %q", key)
+		}
+		seen[key] = true
+		if err := p.expect('='); err != nil {
+			return err
+		}
+
+		switch key {
+		case "attempts":
+			n, err := p.int()
+			if err != nil {
+				return err
+			}
+			cfg.maxAttempts = n
+		case "duration":
+			d, err := p.duration()
+			if err != nil {
+				return err
+			}
+			cfg.maxDuration = d
+		case "backoff":
+			b, err := p.backoff()
+			if err != nil {
+				return err
+			}
+			cfg.backoff = b
+		default:
+			return p.errorf(at, "unknown field %q; expected attempts, duration or backoff", key)
+		}
+
+		// Fields are separated by whitespace.
+		if !p.done() && !isSpace(p.input[p.pos]) {
+			return p.errorf(p.pos, "expected space before next field, found %s", p.peekDesc())
+		}
+	}
+}
+
+// parser is a small recursive-descent parser over a single line of input.
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) backoff() (Backoff, error) {
+	p.skipSpace()
+	name, at := p.word()
+	if name == "" {
+		return nil, p.errorf(at, "expected backoff name, found %s", p.peekDesc())
+	}
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+
+	var b Backoff
+	switch name {
+	case "constant", "const", "linear", "lin", "exponential", "exp":
+		d, err := p.duration()
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case "constant", "const":
+			b = Constant(d)
+		case "linear", "lin":
+			b = Linear(d)
+		default:
+			b = Exponential(d)
+		}
+	case "cap", "min":
+		d, err := p.duration()
+		if err != nil {
+			return nil, err
+		}
+		inner, err := p.wrapped()
+		if err != nil {
+			return nil, err
+		}
+		if name == "cap" {
+			b = WithCap(d, inner)
+		} else {
+			b = WithMin(d, inner)
+		}
+	case "jitter":
+		f, err := p.float()
+		if err != nil {
+			return nil, err
+		}
+		inner, err := p.wrapped()
+		if err != nil {
+			return nil, err
+		}
+		b = WithJitter(f, inner)
+	default:
+		return nil, p.errorf(at, "unknown backoff %q; expected constant, linear, exponential, cap, min or jitter", name)
+	}
+
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// wrapped parses the ", backoff" tail of a wrapper's arguments.
+func (p *parser) wrapped() (Backoff, error) {
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	return p.backoff()
+}
+
+func (p *parser) duration() (time.Duration, error) {
+	p.skipSpace()
+	w, at := p.word()
+	if w == "" {
+		return 0, p.errorf(at, "expected duration, found %s", p.peekDesc())
+	}
+	d, err := time.ParseDuration(w)
+	if err != nil {
+		return 0, p.errorf(at, "invalid duration %q", w)
+	}
+	return d, nil
+}
+
+func (p *parser) int() (int, error) {
+	p.skipSpace()
+	w, at := p.word()
+	if w == "" {
+		return 0, p.errorf(at, "expected integer, found %s", p.peekDesc())
+	}
+	n, err := strconv.Atoi(w)
+	if err != nil {
+		return 0, p.errorf(at, "invalid integer %q", w)
+	}
+	return n, nil
+}
+
+func (p *parser) float() (float64, error) {
+	p.skipSpace()
+	w, at := p.word()
+	if w == "" {
+		return 0, p.errorf(at, "expected number, found %s", p.peekDesc())
+	}
+	f, err := strconv.ParseFloat(w, 64)
+	if err != nil {
+		return 0, p.errorf(at, "invalid number %q", w)
+	}
+	return f, nil
+}
+
+// word consumes a run of characters that are not whitespace or punctuation
+// and returns it with its starting offset.
+func (p *parser) word() (string, int) {
+	start := p.pos
+	for p.pos < len(p.input) && !isSpace(p.input[p.pos]) && !strings.ContainsRune("(),=", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return p.input[start:p.pos], start
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipSpace()
+	if p.done() || p.input[p.pos] != c {
+		return p.errorf(p.pos, "expected %q, found %s", c, p.peekDesc())
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) end() error {
+	p.skipSpace()
+	if !p.done() {
+		return p.errorf(p.pos, "unexpected %s after backoff", p.peekDesc())
+	}
+	return nil
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && isSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+// peekDesc describes the input at the current position for error messages.
+func (p *parser) peekDesc() string {
+	if p.done() {
+		return "end of input"
+	}
+	return strconv.QuoteRune(rune(p.input[p.pos]))
+}
+
+func (p *parser) errorf(offset int, format string, args ...any) error {
+	return &SyntaxError{Input: p.input, Offset: offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// validateParsed reports problems in a parsed backoff as ConfigErrors.
+func validateParsed(b Backoff) error {
+	var errs []error
+	for _, problem := range validateBackoff(b, nil) {
+		errs = append(errs, &ConfigError{Field: "Backoff", Reason: problem})
+	}
+	return errors.Join(errs...)
+}
+
+// checkPrintable reports an error if b cannot be written in the text syntax.
+func checkPrintable(b Backoff) error {
+	for b != nil {
+		switch w := b.(type) {
+		case ConstantBackoff, LinearBackoff, ExponentialBackoff:
+			return nil
+		case CapBackoff:
+			b = w.b
+		case MinBackoff:
+			b = w.b
+		case JitterBackoff:
+			b = w.b
+		default:
+			return fmt.Errorf("retry: cannot marshal %s backoff", backoffString(b))
+		}
+	}
+	return errors.New("retry: cannot marshal nil backoff")
+}
@@ -0,0 +1,210 @@
+package retry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestParseBackoff(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"constant(100ms)", "constant(100ms)"},
+		{"const(1s)", "constant(1s)"},
+		{"linear(1s)", "linear(1s)"},
+		{"lin(1s)", "linear(1s)"},
+		{"exponential(100ms)", "exponential(100ms)"},
+		{"exp(100ms)", "exponential(100ms)"},
+		{"cap(10s,exp(100ms))", "cap(10s, exponential(100ms))"},
+		{"min(1s, linear(100ms))", "min(1s, linear(100ms))"},
+		{"jitter(0.2,cap(10s,exp(100ms)))", "jitter(0.2, cap(10s, exponential(100ms)))"},
+		{"  jitter ( 0.5 , exp ( 1m30s ) )  ", "jitter(0.5, exponential(1m30s))"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			b, err := retry.ParseBackoff(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := b.(interface{ String() string }).String()
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+
+			// The printed form parses back to the same backoff.
+			again, err := retry.ParseBackoff(got)
+			if err != nil {
+				t.Fatalf("reparse %q: %v", got, err)
+			}
+			if again != b {
+				t.Fatalf("expected reparse of %q to be %v, got %v", got, b, again)
+			}
+		})
+	}
+}
+
+func TestParseBackoffErrors(t *testing.T) {
+	cases := []struct {
+		input  string
+		offset int
+		msg    string
+	}{
+		{"", 0, "expected backoff name, found end of input"},
+		{"fib(1s)", 0, `unknown backoff "fib"; expected constant, linear, exponential, cap, min or jitter`},
+		{"exp 100ms", 4, `expected '(', found '1'`},
+		{"exp()", 4, "expected duration, found ')'"},
+		{"exp(fast)", 4, `invalid duration "fast"`},
+		{"jitter(lots, exp(1s))", 7, `invalid number "lots"`},
+		{"cap(10s exp(1s))", 8, `expected ',', found 'e'`},
+		{"jitter(0.2,cap(10s,exp(100ms))", 30, "expected ')', found end of input"},
+		{"exp(1s) extra", 8, "unexpected 'e' after backoff"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := retry.ParseBackoff(tc.input)
+
+			var syntaxErr *retry.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Offset != tc.offset || syntaxErr.Msg != tc.msg {
+				t.Fatalf("expected %q at %d, got %q at %d", tc.msg, tc.offset, syntaxErr.Msg, syntaxErr.Offset)
+			}
+		})
+	}
+
+	t.Run("invalid but well-formed", func(t *testing.T) {
+		_, err := retry.ParseBackoff("jitter(5, exp(1s))")
+
+		var cfgErr *retry.ConfigError
+		if !errors.As(err, &cfgErr) {
+			t.Fatalf("expected *ConfigError, got %v", err)
+		}
+	})
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Run("all fields", func(t *testing.T) {
+		p, err := retry.ParsePolicy("attempts=5 duration=30s backoff=jitter(0.2,cap(10s,exp(100ms)))")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := retry.Description{
+			MaxAttempts: 5,
+			MaxDuration: 30 * time.Second,
+			Backoff:     "jitter(0.2, cap(10s, exponential(100ms)))",
+			Clock:       "real",
+		}
+		if got := p.Describe(); got != want {
+			t.Fatalf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("defaults for absent fields", func(t *testing.T) {
+		p, err := retry.ParsePolicy("  attempts=2  ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := p.String(); got != "attempts=2 backoff=exponential(100ms)" {
+			t.Fatalf("unexpected policy %q", got)
+		}
+	})
+
+	t.Run("String round trips", func(t *testing.T) {
+		for _, p := range []*retry.Policy{
+			retry.Default(),
+			retry.Never(),
+			retry.New(retry.WithMaxDuration(time.Minute), retry.WithBackoff(retry.WithMin(time.Second, retry.Linear(0)))),
+		} {
+			parsed, err := retry.ParsePolicy(p.String())
+			if err != nil {
+				t.Fatalf("parse %q: %v", p, err)
+			}
+			if parsed.String() != p.String() {
+				t.Fatalf("expected %q, got %q", p, parsed)
+			}
+		}
+	})
+
+	errCases := []struct {
+		input  string
+		offset int
+		msg    string
+	}{
+		{"retries=5", 0, `unknown field "retries"; expected attempts, duration or backoff`},
+		{"attempts 5", 9, `expected '=', found '5'`},
+		{"attempts=five", 9, `invalid integer "five"`},
+		{"attempts=1 attempts=2", 11, `duplicate field "attempts"`},
+		{"duration=30", 9, `invalid duration "30"`},
+		{"backoff=exp(1s)attempts=2", 15, "expected space before next field, found 'a'"},
+		{"=5", 0, "expected field name, found '='"},
+	}
+	for _, tc := range errCases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := retry.ParsePolicy(tc.input)
+
+			var syntaxErr *retry.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Offset != tc.offset || syntaxErr.Msg != tc.msg {
+				t.Fatalf("expected %q at %d, got %q at %d", tc.msg, tc.offset, syntaxErr.Msg, syntaxErr.Offset)
+			}
+		})
+	}
+
+	t.Run("validates", func(t *testing.T) {
+		_, err := retry.ParsePolicy("attempts=-1")
+
+		var cfgErr *retry.ConfigError
+		if !errors.As(err, &cfgErr) || cfgErr.Field != "MaxAttempts" {
+			t.Fatalf("expected MaxAttempts *ConfigError, got %v", err)
+		}
+	})
+}
+
+func TestPolicyText(t *testing.T) {
+	t.Run("marshal", func(t *testing.T) {
+		text, err := retry.Default().MarshalText()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(text) != "attempts=3 backoff=jitter(0.2, cap(10s, exponential(100ms)))" {
+			t.Fatalf("unexpected text %q", text)
+		}
+	})
+
+	t.Run("marshal custom backoff fails", func(t *testing.T) {
+		p := retry.New(retry.WithBackoff(retry.BackoffFunc(func(int) time.Duration { return 0 })))
+		if _, err := p.MarshalText(); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("unmarshal onto existing policy", func(t *testing.T) {
+		p := retry.New(retry.WithMaxDuration(time.Minute), retry.WithAllErrors())
+		if err := p.UnmarshalText([]byte("attempts=7")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d := p.Describe()
+		if d.MaxAttempts != 7 || d.MaxDuration != time.Minute || !d.AllErrors {
+			t.Fatalf("unexpected policy %+v", d)
+		}
+	})
+
+	t.Run("unmarshal into zero policy", func(t *testing.T) {
+		var p retry.Policy
+		if err := p.UnmarshalText([]byte("backoff=const(1s)")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := p.String(); got != "attempts=3 backoff=constant(1s)" {
+			t.Fatalf("unexpected policy %q", got)
+		}
+	})
+}
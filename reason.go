@@ -0,0 +1,47 @@
+package retry
+
+// StopReason describes why the retry loop stopped without succeeding.
+type StopReason int
+
+const (
+	// StopExhausted means the maximum number of attempts was reached.
+	StopExhausted StopReason = iota + 1
+	// StopMaxDuration means the MaxDuration budget ran out.
+	StopMaxDuration
+	// StopCondition means the If condition rejected the error.
+	StopCondition
+	// StopTerminal means the error was wrapped with Stop or marked Terminal.
+	StopTerminal
+	// StopCanceled means a BeforeRetry hook canceled the retry.
+	StopCanceled
+	// StopContext means the context was done while waiting to retry.
+	StopContext
+	// StopRemedy means a remediation registered with StopOnFailure failed.
+	StopRemedy
+	// StopShutdown means the policy was shut down. See Policy.Shutdown.
+	StopShutdown
+)
+
+// String returns a short lowercase name for the reason.
+func (r StopReason) String() string {
+	switch r {
+	case StopExhausted:
+		return "exhausted"
+	case StopMaxDuration:
+		return "max_duration"
+	case StopCondition:
+		return "condition"
+	case StopTerminal:
+		return "terminal"
+	case StopCanceled:
+		return "canceled"
+	case StopContext:
+		return "context"
+	case StopRemedy:
+		return "remedy"
+	case StopShutdown:
+		return "shutdown"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,277 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OnReloadErrorFunc is called when a Registry fails to reload its file.
+// The registry keeps serving the policies it had before.
+type OnReloadErrorFunc func(ctx context.Context, path string, err error)
+
+// OnReloadFunc is called after a Registry reloads its file.
+type OnReloadFunc func(ctx context.Context, path string)
+
+// RegistryOption configures a Registry.
+type RegistryOption func(*Registry)
+
+// OnReloadError adds a hook called when Watch fails to reload the file.
+func OnReloadError(fn OnReloadErrorFunc) RegistryOption {
+	return func(r *Registry) {
+		r.onReloadError = append(r.onReloadError, fn)
+	}
+}
+
+// OnReload adds a hook called after Watch reloads a changed file.
+func OnReload(fn OnReloadFunc) RegistryOption {
+	return func(r *Registry) {
+		r.onReload = append(r.onReload, fn)
+	}
+}
+
+// Registry holds named policies, such as "db" or "payments-api", that can be
+// loaded from a JSON file and reloaded while the program runs.
+//
+// The file is a JSON object mapping names to policies in the form described
+// by JSONSchema:
+//
+//	{
+//	  "db":           {"maxAttempts": 5, "backoff": {"type": "exponential", "base": "100ms"}},
+//	  "payments-api": {"maxDuration": "30s"}
+//	}
+//
+// Policies registered in code with Register are the base for entries of the
+// same name, so their hooks, conditions and other code-only options are kept
+// and the file only overrides the budget and backoff.
+//
+// Reloads swap the whole set atomically. A Do call already running finishes
+// under the policy it started with; later calls to Get see the new one. Look
+// policies up per call rather than holding on to them to pick up reloads.
+// Runtime overrides (see Policy.SetOverrides) set on a registry policy carry
+// over to its replacement.
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu            sync.Mutex // serializes updates
+	base          map[string]*Policy
+	file          map[string]json.RawMessage
+	stamp         fileStamp
+	policies      atomic.Pointer[map[string]*Policy]
+	onReload      []OnReloadFunc
+	onReloadError []OnReloadErrorFunc
+}
+
+// fileStamp identifies a version of a file for change detection.
+type fileStamp struct {
+	path    string
+	modTime time.Time
+	size    int64
+	err     string
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{base: map[string]*Policy{}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.policies.Store(&map[string]*Policy{})
+	return r
+}
+
+// Register sets the base policy for name. If the loaded file has an entry for
+// name, it is applied on top of p; otherwise p is used as is. Either way, the
+// served policy shares p's runtime state, so SetOverrides, Shutdown and
+// InFlight on p cover calls made through the registry. Passing nil removes
+// the base policy.
+func (r *Registry) Register(name string, p *Policy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	base := make(map[string]*Policy, len(r.base)+1)
+	for n, bp := range r.base {
+		base[n] = bp
+	}
+	if p == nil {
+		delete(base, name)
+	} else {
+		base[name] = p
+	}
+	return r.update(base, r.file)
+}
+
+// Load replaces the file-defined policies with those decoded from data. If any
+// entry is invalid, nothing is replaced and every problem is returned.
+func (r *Registry) Load(data []byte) error {
+	var file map[string]json.RawMessage
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("retry: registry: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.update(r.base, file)
+}
+
+// LoadFile loads the policies from the JSON file at path, as Load does.
+func (r *Registry) LoadFile(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loadFile(path, statFile(path))
+}
+
+// Watch polls the file at path every interval and reloads it when it changes,
+// until ctx is done. Watch blocks, so it is usually run in its own goroutine
+// after an initial LoadFile. Failed reloads are reported to the OnReloadError
+// hooks and the previous policies stay in place. Watch returns an error at
+// once if interval is not positive.
+func (r *Registry) Watch(ctx context.Context, path string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("retry: registry: watch interval must be positive, got %v", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		stamp := statFile(path)
+		r.mu.Lock()
+		if stamp == r.stamp {
+			r.mu.Unlock()
+			continue
+		}
+		err := r.loadFile(path, stamp)
+		r.mu.Unlock()
+
+		if err != nil {
+			for _, fn := range r.onReloadError {
+				protect(func() { fn(ctx, path, err) })
+			}
+			continue
+		}
+		for _, fn := range r.onReload {
+			protect(func() { fn(ctx, path) })
+		}
+	}
+}
+
+// Get returns the policy registered as name, or DefaultPolicy if there is none.
+func (r *Registry) Get(name string) *Policy {
+	if p, ok := r.Lookup(name); ok {
+		return p
+	}
+	return DefaultPolicy()
+}
+
+// Lookup returns the policy registered as name and whether it exists.
+func (r *Registry) Lookup(name string) (*Policy, bool) {
+	p, ok := (*r.policies.Load())[name]
+	return p, ok
+}
+
+// Names returns the names of all policies in sorted order.
+func (r *Registry) Names() []string {
+	policies := *r.policies.Load()
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// InFlight returns the tracked calls running under any of the registry's
+// policies, oldest first. See WithTracking.
+func (r *Registry) InFlight() []InFlightCall {
+	var calls []InFlightCall
+	for _, p := range *r.policies.Load() {
+		calls = append(calls, p.InFlight()...)
+	}
+	slices.SortFunc(calls, func(a, b InFlightCall) int { return a.Started.Compare(b.Started) })
+	return calls
+}
+
+// Do executes fn with retry using the policy registered as name.
+func (r *Registry) Do(ctx context.Context, name string, fn Func, opts ...Option) error {
+	return r.Get(name).Do(ctx, fn, opts...)
+}
+
+// loadFile reads path and applies it. The stamp is recorded even on failure so
+// Watch reports each broken version of the file only once.
+func (r *Registry) loadFile(path string, stamp fileStamp) error {
+	r.stamp = stamp
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("retry: registry: %w", err)
+	}
+	var file map[string]json.RawMessage
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("retry: registry %s: %w", path, err)
+	}
+	return r.update(r.base, file)
+}
+
+// update builds the policy set from base and file and swaps it in.
+// r.mu must be held.
+func (r *Registry) update(base map[string]*Policy, file map[string]json.RawMessage) error {
+	policies := make(map[string]*Policy, len(base)+len(file))
+	for name, p := range base {
+		policies[name] = p
+	}
+
+	var errs []error
+	for name, data := range file {
+		p := &Policy{}
+		if bp, ok := base[name]; ok {
+			// Share the registered policy's overrides, shutdown and tracking.
+			p = bp.With()
+			p.ctl = bp.ctl
+		}
+		if err := p.UnmarshalJSON(data); err != nil {
+			errs = append(errs, fmt.Errorf("retry: registry policy %q: %w", name, err))
+			continue
+		}
+		policies[name] = p
+	}
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+		return errors.Join(errs...)
+	}
+
+	// Overrides set on live file-only policies carry over to their
+	// replacements.
+	for name, p := range *r.policies.Load() {
+		if _, ok := base[name]; ok {
+			continue
+		}
+		if np, ok := policies[name]; ok {
+			np.ctl = p.ctl
+		}
+	}
+
+	r.base = base
+	r.file = file
+	r.policies.Store(&policies)
+	return nil
+}
+
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{path: path, err: err.Error()}
+	}
+	return fileStamp{path: path, modTime: info.ModTime(), size: info.Size()}
+}
@@ -0,0 +1,256 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+// writeFile replaces path atomically, so a watcher never sees a partial file.
+func writeFile(t *testing.T, path, data string, mod time.Time) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(tmp, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("loads named policies", func(t *testing.T) {
+		r := retry.NewRegistry()
+		err := r.Load([]byte(`{
+			"db": {"maxAttempts": 5, "backoff": {"type": "constant", "delay": "1s"}},
+			"payments-api": {"maxDuration": "30s"}
+		}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := r.Names(); strings.Join(got, ",") != "db,payments-api" {
+			t.Fatalf("unexpected names %v", got)
+		}
+		if got := r.Get("db").String(); got != "attempts=5 backoff=constant(1s)" {
+			t.Fatalf("unexpected db policy %s", got)
+		}
+		if got := r.Get("payments-api").Describe().MaxDuration; got != 30*time.Second {
+			t.Fatalf("unexpected payments-api duration %v", got)
+		}
+	})
+
+	t.Run("unknown names use the default policy", func(t *testing.T) {
+		r := retry.NewRegistry()
+		if _, ok := r.Lookup("missing"); ok {
+			t.Fatal("expected missing policy")
+		}
+		if r.Get("missing") != retry.DefaultPolicy() {
+			t.Fatal("expected default policy")
+		}
+	})
+
+	t.Run("file entries overlay registered policies", func(t *testing.T) {
+		var retried bool
+		r := retry.NewRegistry()
+		base := retry.New(
+			retry.WithClock(newFakeClock()),
+			retry.OnRetry(func(context.Context, int, error, time.Duration) { retried = true }),
+		)
+		if err := r.Register("db", base); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Load([]byte(`{"db": {"maxAttempts": 2}}`)); err != nil {
+			t.Fatal(err)
+		}
+
+		calls := 0
+		_ = r.Do(context.Background(), "db", func(context.Context) error {
+			calls++
+			return errTest
+		})
+		if calls != 2 || !retried {
+			t.Fatalf("expected 2 calls with hooks, got %d (retried=%v)", calls, retried)
+		}
+
+		if err := r.Load([]byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+		if r.Get("db") != base {
+			t.Fatal("expected registered policy once the file entry is gone")
+		}
+
+		if err := r.Register("db", nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := r.Lookup("db"); ok {
+			t.Fatal("expected db to be removed")
+		}
+	})
+
+	t.Run("file entries share the registered policy's state", func(t *testing.T) {
+		r := retry.NewRegistry()
+		base := retry.New(retry.WithClock(newFakeClock()), retry.WithTracking("db"))
+		if err := r.Register("db", base); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Load([]byte(`{"db": {"maxAttempts": 5}}`)); err != nil {
+			t.Fatal(err)
+		}
+		base.SetOverrides(retry.Overrides{MaxAttempts: 2})
+
+		calls := 0
+		var inFlight []retry.InFlightCall
+		_ = r.Do(context.Background(), "db", func(context.Context) error {
+			calls++
+			inFlight = base.InFlight()
+			return errTest
+		})
+		if calls != 2 {
+			t.Fatalf("expected the registered policy's override to apply, got %d calls", calls)
+		}
+		if len(inFlight) != 1 || inFlight[0].Name != "db" {
+			t.Fatalf("expected the call in the registered policy's InFlight, got %+v", inFlight)
+		}
+
+		if err := base.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		err := r.Do(context.Background(), "db", func(context.Context) error { return errTest })
+		if !errors.Is(err, retry.ErrShuttingDown) {
+			t.Fatalf("expected ErrShuttingDown, got %v", err)
+		}
+	})
+
+	t.Run("invalid files change nothing", func(t *testing.T) {
+		r := retry.NewRegistry()
+		if err := r.Load([]byte(`{"db": {"maxAttempts": 5}}`)); err != nil {
+			t.Fatal(err)
+		}
+		before := r.Get("db")
+
+		err := r.Load([]byte(`{"db": {"maxAttempts": 2}, "a": {"maxAttempts": -1}, "b": {"maxDuration": "soon"}}`))
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		for _, want := range []string{`registry policy "a"`, `registry policy "b"`} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q in:\n%v", want, err)
+			}
+		}
+		if r.Get("db") != before {
+			t.Fatal("expected previous policies to be kept")
+		}
+
+		if err := r.Load([]byte(`[]`)); err == nil {
+			t.Fatal("expected error for non-object")
+		}
+	})
+}
+
+func TestRegistryWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retry.json")
+	mod := time.Now().Add(-time.Hour)
+	writeFile(t, path, `{"db": {"maxAttempts": 2}}`, mod)
+
+	reloads := make(chan struct{}, 1)
+	reloadErrs := make(chan error, 1)
+	r := retry.NewRegistry(
+		retry.OnReload(func(_ context.Context, p string) {
+			if p != path {
+				t.Errorf("unexpected path %s", p)
+			}
+			reloads <- struct{}{}
+		}),
+		retry.OnReloadError(func(_ context.Context, p string, err error) {
+			if p != path {
+				t.Errorf("unexpected path %s", p)
+			}
+			reloadErrs <- err
+		}),
+	)
+	if err := r.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Watch(ctx, path, time.Millisecond) }()
+
+	attempts := func() int { return r.Get("db").Describe().MaxAttempts }
+
+	// A running Do keeps the policy it started with.
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	var calls int
+	go func() {
+		_ = r.Do(ctx, "db", func(context.Context) error {
+			calls++
+			if calls == 1 {
+				close(started)
+				<-release
+			}
+			return errTest
+		}, retry.WithBackoff(retry.Constant(0)))
+		close(finished)
+	}()
+	<-started
+
+	mod = mod.Add(time.Minute)
+	writeFile(t, path, `{"db": {"maxAttempts": 4}}`, mod)
+	<-reloads
+	if attempts() != 4 {
+		t.Fatalf("expected 4 attempts after reload, got %d", attempts())
+	}
+	close(release)
+	<-finished
+	if calls != 2 {
+		t.Fatalf("expected the running call to keep 2 attempts, got %d", calls)
+	}
+
+	mod = mod.Add(time.Minute)
+	writeFile(t, path, `{"db": {"maxAttempts": "many"}}`, mod)
+	if err := <-reloadErrs; !strings.Contains(err.Error(), `registry policy "db"`) {
+		t.Fatalf("unexpected reload error %v", err)
+	}
+	if attempts() != 4 {
+		t.Fatalf("expected previous policy to be kept, got %d attempts", attempts())
+	}
+
+	mod = mod.Add(time.Minute)
+	writeFile(t, path, `{"db": {"maxAttempts": 6}}`, mod)
+	// The broken version was reported once, not on every poll.
+	select {
+	case <-reloads:
+	case err := <-reloadErrs:
+		t.Fatalf("expected each broken version reported once, got %v", err)
+	}
+	if attempts() != 6 {
+		t.Fatalf("expected 6 attempts after reload, got %d", attempts())
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRegistryWatchInterval(t *testing.T) {
+	r := retry.NewRegistry()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		err := r.Watch(context.Background(), "retry.json", interval)
+		if err == nil || !strings.Contains(err.Error(), "interval must be positive") {
+			t.Fatalf("expected an interval error for %v, got %v", interval, err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package retry
+
+import (
+	"context"
+	"errors"
+)
+
+// Retrier executes functions with retry. *Policy implements it.
+// Depend on Retrier instead of *Policy to make injecting test doubles easy.
+type Retrier interface {
+	Do(ctx context.Context, fn Func, opts ...Option) error
+}
+
+var _ Retrier = (*Policy)(nil)
+
+// NoRetry is a Retrier that runs fn exactly once and ignores all options.
+// Errors wrapped with Stop are unwrapped, as Policy.Do would.
+var NoRetry Retrier = noRetry{}
+
+type noRetry struct{}
+
+func (noRetry) Do(ctx context.Context, fn Func, _ ...Option) error {
+	err := fn(ctx)
+	var stopped *stopError
+	if errors.As(err, &stopped) {
+		return stopped.Unwrap()
+	}
+	return err
+}
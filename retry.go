@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,12 +22,39 @@ type OnSuccessFunc func(ctx context.Context, attempts int)
 // OnExhaustedFunc is called when all retry attempts are exhausted.
 type OnExhaustedFunc func(ctx context.Context, attempts int, err error)
 
+// OnAttemptStartFunc is called before each attempt, including the first.
+type OnAttemptStartFunc func(ctx context.Context, attempt int)
+
+// OnAttemptEndFunc is called after each attempt with its result and how long it took.
+type OnAttemptEndFunc func(ctx context.Context, attempt int, err error, duration time.Duration)
+
+// OnGiveUpFunc is called whenever the retry loop stops without succeeding.
+// err is the error returned to the caller.
+type OnGiveUpFunc func(ctx context.Context, attempts int, err error, reason StopReason)
+
+// OnHookPanicFunc is called with the value recovered from a hook that
+// panicked.
+type OnHookPanicFunc func(ctx context.Context, recovered any)
+
+// BeforeRetryFunc is called before each retry sleep and decides whether
+// and when the retry happens.
+type BeforeRetryFunc func(ctx context.Context, attempt int, err error, delay time.Duration) Decision
+
+// RemedyFunc performs a corrective action between attempts, such as
+// refreshing a credential or re-establishing a connection.
+type RemedyFunc func(ctx context.Context, err error) error
+
 // Policy defines retry behavior. Safe for concurrent use.
+//
+// A Policy keeps every option it was created with, including call-level
+// options such as If, hooks and WithAllErrors, as defaults. Options passed to
+// Do are applied on top of them.
+//
+// Runtime overrides set with SetOverrides adjust a live policy without
+// rebuilding it.
 type Policy struct {
-	maxAttempts int
-	maxDuration time.Duration
-	backoff     Backoff
-	clock       Clock
+	cfg config
+	ctl *control
 }
 
 // Default values.
@@ -34,28 +62,53 @@ const (
 	DefaultMaxAttempts = 3
 )
 
-// package-level defaults to avoid allocation
-var (
-	defaultBackoff = Exponential(100 * time.Millisecond)
-	defaultClock   = realClock{}
-)
+// defaultPolicy is the policy used by the package-level Do.
+var defaultPolicy atomic.Pointer[Policy]
+
+func init() {
+	defaultPolicy.Store(Default())
+}
+
+// SetDefault replaces the policy used by the package-level Do.
+// It is safe to call concurrently with Do; calls already running keep the
+// policy they started with. Passing nil restores Default().
+//
+// SetDefault is intended to be called once at startup so that every library
+// using retry.Do picks up an application-wide policy.
+func SetDefault(p *Policy) {
+	if p == nil {
+		p = Default()
+	}
+	defaultPolicy.Store(p)
+}
+
+// DefaultPolicy returns the policy used by the package-level Do.
+func DefaultPolicy() *Policy {
+	return defaultPolicy.Load()
+}
 
 // New creates a Policy with the given options.
 func New(opts ...Option) *Policy {
-	cfg := &config{
+	cfg := config{
 		maxAttempts: DefaultMaxAttempts,
 		backoff:     Exponential(100 * time.Millisecond),
 		clock:       realClock{},
 	}
 	for _, opt := range opts {
-		opt(cfg)
+		opt(&cfg)
 	}
-	return &Policy{
-		maxAttempts: cfg.maxAttempts,
-		maxDuration: cfg.maxDuration,
-		backoff:     cfg.backoff,
-		clock:       cfg.clock,
+	return &Policy{cfg: cfg.clip(), ctl: newControl()}
+}
+
+// With returns a new Policy that starts from this policy's options and applies
+// opts on top. The receiver is not modified, so a shared base policy can be
+// specialized safely.
+func (p *Policy) With(opts ...Option) *Policy {
+	cfg := p.cfg
+	for _, opt := range opts {
+		opt(&cfg)
 	}
+	return &Policy{cfg: cfg.clip(), ctl: newControl()}
 }
 
 // Never returns a policy that does not retry.
@@ -72,35 +125,39 @@ func Default() *Policy {
 }
 
 // Do executes fn with retry using the default policy.
+// See SetDefault to change it.
 func Do(ctx context.Context, fn Func, opts ...Option) error {
-	cfg := config{
-		maxAttempts: DefaultMaxAttempts,
-		backoff:     defaultBackoff,
-		clock:       defaultClock,
-		condition:   defaultCondition,
-	}
-	for _, opt := range opts {
-		opt(&cfg)
-	}
-	return execute(ctx, fn, cfg)
+	return DefaultPolicy().Do(ctx, fn, opts...)
 }
 
 // Do executes fn with retry using this policy's configuration.
 func (p *Policy) Do(ctx context.Context, fn Func, opts ...Option) error {
-	cfg := config{
-		maxAttempts: p.maxAttempts,
-		maxDuration: p.maxDuration,
-		backoff:     p.backoff,
-		clock:       p.clock,
-		condition:   defaultCondition,
+	if len(opts) == 0 {
+		// Options take the config's address, moving it to the heap.
+		return execute(ctx, fn, p.cfg, p.ctl)
 	}
+	cfg := p.cfg
 	for _, opt := range opts {
 		opt(&cfg)
 	}
-	return execute(ctx, fn, cfg)
+	return execute(ctx, fn, cfg, p.ctl)
 }
 
-func execute(ctx context.Context, fn Func, cfg config) error {
+// Retryable reports whether this policy would retry err, ignoring attempt and
+// time budgets. It returns false for nil errors, errors wrapped with Stop, and
+// errors rejected by the If condition.
+func (p *Policy) Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var stopped *stopError
+	if errors.As(err, &stopped) {
+		return false
+	}
+	return p.cfg.condition == nil || p.cfg.condition(err)
+}
+
+func execute(ctx context.Context, fn Func, cfg config, ctl *control) error {
 	var lastErr error
 	var errs []error
 	var deadline time.Time
@@ -114,87 +171,234 @@ func execute(ctx context.Context, fn Func, cfg config) error {
 		maxAttempts = DefaultMaxAttempts
 	}
 
+	// result returns the error reported when the loop gives up.
+	result := func() error {
+		if cfg.allErrors {
+			return joinErrors(errs)
+		}
+		return lastErr
+	}
+
+	op, named := cfg.operation()
+	if named {
+		ctx = context.WithValue(ctx, operationKey{}, op)
+	}
+
+	ctx, call := startCall(ctx, cfg.tracer, maxAttempts, op)
+	ctx, endTask := startTask(ctx, &cfg)
+	defer endTask()
+
+	// The call's start time is only recorded when something can read it,
+	// so calls without hooks or middleware allocate nothing for it.
+	var began time.Time
+	if len(cfg.middleware) > 0 || !cfg.hooks.empty() {
+		began = cfg.clock.Now()
+		ctx = context.WithValue(ctx, callKey{}, CallInfo{Started: began, clock: cfg.clock})
+	}
+
+	// giveUp reports a terminal failure to the hooks and returns err, wrapped
+	// in an OperationError for named calls.
+	giveUp := func(attempt int, err error, reason StopReason) error {
+		if named {
+			err = &OperationError{Operation: op, Attempts: attempt, Reason: reason, Err: err}
+		}
+		cfg.hooks.giveUp(ctx, attempt, err, reason)
+		call.end(attempt, err, reason)
+		return err
+	}
+
+	if len(cfg.middleware) > 0 {
+		fn = chain(fn, cfg.middleware)
+	}
+
+	// Shutdown waits for every call on the policy to return.
+	if ctl != nil {
+		ctl.life.begin()
+		defer ctl.life.end()
+	}
+
+	// waitCtx is used for sleeping between attempts and is also canceled by
+	// Shutdown. It is created on the first sleep, so calls that succeed or
+	// retry without delay allocate nothing for it.
+	waitCtx := ctx
+	var cancelWait context.CancelFunc
+	var stopWait func() bool
+	defer func() {
+		if cancelWait != nil {
+			stopWait()
+			cancelWait()
+		}
+	}()
+
+	// interrupted reports a wait cut short by the context or by Shutdown.
+	interrupted := func(attempt int) error {
+		if ctx.Err() != nil {
+			return giveUp(attempt, result(), StopContext)
+		}
+		return giveUp(attempt, shutdownError(result()), StopShutdown)
+	}
+
+	var fl *flight
+	if cfg.tracked && ctl != nil {
+		name := cfg.trackName
+		if name == "" {
+			name = cfg.name
+		}
+		fl = ctl.flights.add(name, cfg.clock)
+		defer ctl.flights.remove(fl)
+	}
+
+	var err error
 	for attempt := 1; ; attempt++ {
-		err := fn(ctx)
+		attemptCtx := ctx
+		if len(cfg.middleware) > 0 {
+			attemptCtx = context.WithValue(ctx, attemptKey{}, AttemptInfo{
+				Attempt:     attempt,
+				MaxAttempts: ctl.load().limit(maxAttempts),
+				Elapsed:     cfg.clock.Now().Sub(began),
+				LastErr:     err,
+			})
+		}
+		attemptCtx, attemptSpan := call.attempt(attemptCtx, attempt)
+
+		fl.attempting(attempt)
+		cfg.hooks.attemptStart(ctx, attempt)
+		var start time.Time
+		if len(cfg.hooks.onAttemptEnd) > 0 {
+			start = cfg.clock.Now()
+		}
+		err = runAttempt(attemptCtx, &cfg, fn, attempt)
+		endSpan(attemptSpan, err)
+		if len(cfg.hooks.onAttemptEnd) > 0 {
+			cfg.hooks.attemptEnd(ctx, attempt, err, cfg.clock.Now().Sub(start))
+		}
 		if err == nil {
-			if cfg.onSuccess != nil {
-				cfg.onSuccess(ctx, attempt)
-			}
+			cfg.hooks.success(ctx, attempt)
+			call.end(attempt, nil, 0)
 			return nil
 		}
 
 		// Check for terminal error
 		var stopped *stopError
 		if errors.As(err, &stopped) {
-			return stopped.Unwrap()
+			return giveUp(attempt, stopped.Unwrap(), StopTerminal)
 		}
 
 		// Collect or replace error
+		fl.failed(err)
 		if cfg.allErrors {
 			errs = append(errs, err)
 		} else {
 			lastErr = err
 		}
 
-		// Check if we've exhausted attempts
-		if attempt >= maxAttempts {
-			if cfg.onExhausted != nil {
-				cfg.onExhausted(ctx, attempt, err)
-			}
-			if cfg.allErrors {
-				return joinErrors(errs)
-			}
-			return lastErr
+		// Check if we've exhausted attempts, honoring overrides set since the
+		// loop started
+		overrides := ctl.load()
+		if attempt >= overrides.limit(maxAttempts) {
+			cfg.hooks.exhausted(ctx, attempt, err)
+			return giveUp(attempt, result(), StopExhausted)
 		}
 
 		// Check condition
 		if cfg.condition != nil && !cfg.condition(err) {
-			if cfg.allErrors {
-				return joinErrors(errs)
-			}
-			return lastErr
+			return giveUp(attempt, result(), StopCondition)
 		}
 
 		// Check time budget
 		if cfg.maxDuration > 0 && cfg.clock.Now().After(deadline) {
-			if cfg.onExhausted != nil {
-				cfg.onExhausted(ctx, attempt, err)
+			cfg.hooks.exhausted(ctx, attempt, err)
+			return giveUp(attempt, result(), StopMaxDuration)
+		}
+
+		// Stop retrying once the policy is shutting down
+		if ctl != nil && ctl.life.shuttingDown() {
+			return giveUp(attempt, shutdownError(result()), StopShutdown)
+		}
+
+		// Run remediations
+		skipDelay := false
+		for _, r := range cfg.remedies {
+			if r.cond != nil && !r.cond(err) {
+				continue
+			}
+			if rerr := r.fn(ctx, err); rerr != nil {
+				if r.stopOnFailure {
+					return giveUp(attempt, errors.Join(result(), rerr), StopRemedy)
+				}
+				continue
 			}
-			if cfg.allErrors {
-				return joinErrors(errs)
+			if r.skipDelay {
+				skipDelay = true
 			}
-			return lastErr
 		}
 
 		// Calculate delay
-		delay := cfg.backoff.Delay(attempt)
+		var delay time.Duration
+		if !skipDelay {
+			delay = overrides.scale(cfg.backoff.Delay(attempt))
+		}
 
 		// Check if delay would exceed deadline
 		if cfg.maxDuration > 0 {
 			remaining := deadline.Sub(cfg.clock.Now())
+			if remaining <= 0 {
+				cfg.hooks.exhausted(ctx, attempt, err)
+				return giveUp(attempt, result(), StopMaxDuration)
+			}
 			if delay > remaining {
 				delay = remaining
 			}
-			if delay <= 0 {
-				if cfg.onExhausted != nil {
-					cfg.onExhausted(ctx, attempt, err)
-				}
-				if cfg.allErrors {
-					return joinErrors(errs)
+		}
+
+		// Consult the decision hook
+		if len(cfg.hooks.beforeRetry) > 0 {
+			d := cfg.hooks.decide(ctx, attempt, err, delay)
+			switch d.action {
+			case actionCancel:
+				return giveUp(attempt, result(), StopCanceled)
+			case actionTerminal:
+				return giveUp(attempt, err, StopTerminal)
+			}
+			if d.setDelay {
+				delay = max(d.delay, 0)
+				if cfg.maxDuration > 0 {
+					delay = min(delay, deadline.Sub(cfg.clock.Now()))
 				}
-				return lastErr
 			}
 		}
 
-		if cfg.onRetry != nil {
-			cfg.onRetry(ctx, attempt, err, delay)
+		cfg.hooks.retry(ctx, attempt, err, delay)
+		if fl != nil {
+			fl.sleeping(cfg.clock.Now().Add(delay))
 		}
 
-		if err := cfg.clock.Sleep(ctx, delay); err != nil {
-			if cfg.allErrors {
-				return joinErrors(errs)
-			}
-			return lastErr
+		if delay > 0 && ctl != nil && cancelWait == nil {
+			waitCtx, cancelWait, stopWait = ctl.life.waitContext(ctx)
+		}
+		sleepSpan := call.sleep(ctx, attempt, delay)
+		endRegion := sleepRegion(ctx, &cfg)
+		if err := cfg.clock.Sleep(waitCtx, delay); err != nil {
+			endRegion()
+			endSpan(sleepSpan, err)
+			return interrupted(attempt)
+		}
+
+		// Hold the next attempt while the policy is paused
+		expired, werr := ctl.waitWhilePaused(waitCtx, cfg.clock, deadline, fl)
+		endRegion()
+		endSpan(sleepSpan, werr)
+		if werr != nil {
+			return interrupted(attempt)
+		}
+		if expired {
+			cfg.hooks.exhausted(ctx, attempt, err)
+			return giveUp(attempt, result(), StopMaxDuration)
+		}
+
+		// Don't start another attempt if Shutdown began during the wait
+		if ctl != nil && ctl.life.shuttingDown() {
+			return giveUp(attempt, shutdownError(result()), StopShutdown)
 		}
 	}
 }
@@ -205,7 +409,3 @@ func joinErrors(errs []error) error {
 	}
 	return errors.Join(errs...)
 }
-
-func defaultCondition(err error) bool {
-	return err != nil
-}
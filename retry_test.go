@@ -3,6 +3,7 @@ package retry_test
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -40,6 +41,34 @@ func (c *fakeClock) Advance(d time.Duration) {
 	c.now = c.now.Add(d)
 }
 
+// blockingClock reads the real time, but its Sleep reports each wait on
+// sleeping and then blocks until ctx is done, so tests can act while a call
+// is known to be waiting. Zero-length sleeps return at once.
+type blockingClock struct {
+	sleeping chan time.Duration
+}
+
+func newBlockingClock() *blockingClock {
+	return &blockingClock{sleeping: make(chan time.Duration)}
+}
+
+func (c *blockingClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c *blockingClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d == 0 {
+		return nil
+	}
+	select {
+	case c.sleeping <- d:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
 func TestDo(t *testing.T) {
 	t.Run("succeeds on first attempt", func(t *testing.T) {
 		attempts := 0
@@ -235,6 +264,122 @@ func TestPolicy(t *testing.T) {
 		}
 	})
 
+	t.Run("retains call-level options as defaults", func(t *testing.T) {
+		nonRetryable := errors.New("non-retryable")
+		var exhausted int
+		policy := retry.New(
+			retry.WithMaxAttempts(5),
+			retry.WithClock(newFakeClock()),
+			retry.If(func(err error) bool { return !errors.Is(err, nonRetryable) }),
+			retry.OnGiveUp(func(ctx context.Context, attempts int, err error, reason retry.StopReason) {
+				exhausted++
+			}),
+			retry.WithAllErrors(),
+		)
+
+		attempts := 0
+		err := policy.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 2 {
+				return nonRetryable
+			}
+			return errTest
+		})
+
+		if attempts != 2 {
+			t.Fatalf("expected policy condition to stop after 2 attempts, got %d", attempts)
+		}
+		if !errors.Is(err, errTest) || !errors.Is(err, nonRetryable) {
+			t.Fatalf("expected all errors to be collected, got %v", err)
+		}
+		if exhausted != 1 {
+			t.Fatalf("expected policy hook to run once, got %d", exhausted)
+		}
+	})
+
+	t.Run("call-site hooks layer on policy hooks", func(t *testing.T) {
+		var calls []string
+		policy := retry.New(
+			retry.WithMaxAttempts(2),
+			retry.WithClock(newFakeClock()),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				calls = append(calls, "policy")
+			}),
+		)
+
+		_ = policy.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		}, retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+			calls = append(calls, "call")
+		}))
+
+		if len(calls) != 2 || calls[0] != "policy" || calls[1] != "call" {
+			t.Fatalf("expected [policy call], got %v", calls)
+		}
+	})
+
+	t.Run("With derives without mutating the base", func(t *testing.T) {
+		var calls []string
+		base := retry.New(
+			retry.WithMaxAttempts(2),
+			retry.WithClock(newFakeClock()),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				calls = append(calls, "base")
+			}),
+		)
+		child := base.With(
+			retry.WithMaxAttempts(4),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				calls = append(calls, "child")
+			}),
+		)
+
+		baseAttempts := 0
+		_ = base.Do(context.Background(), func(ctx context.Context) error {
+			baseAttempts++
+			return errTest
+		})
+		if baseAttempts != 2 {
+			t.Fatalf("expected base to keep 2 attempts, got %d", baseAttempts)
+		}
+		if len(calls) != 1 || calls[0] != "base" {
+			t.Fatalf("expected base hooks only, got %v", calls)
+		}
+
+		calls = nil
+		childAttempts := 0
+		_ = child.Do(context.Background(), func(ctx context.Context) error {
+			childAttempts++
+			return errTest
+		})
+		if childAttempts != 4 {
+			t.Fatalf("expected child to use 4 attempts, got %d", childAttempts)
+		}
+		if len(calls) != 6 {
+			t.Fatalf("expected base and child hooks for 3 retries, got %v", calls)
+		}
+	})
+
+	t.Run("concurrent calls do not share option state", func(t *testing.T) {
+		policy := retry.New(
+			retry.WithMaxAttempts(2),
+			retry.WithBackoff(retry.Constant(0)),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {}),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {}),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {}),
+		)
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() {
+				_ = policy.Do(context.Background(), func(ctx context.Context) error {
+					return errTest
+				}, retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {}))
+			})
+		}
+		wg.Wait()
+	})
+
 	t.Run("Never policy does not retry", func(t *testing.T) {
 		attempts := 0
 		err := retry.Never().Do(context.Background(), func(ctx context.Context) error {
@@ -326,6 +471,268 @@ func TestHooks(t *testing.T) {
 	})
 }
 
+func TestComposableHooks(t *testing.T) {
+	t.Run("hooks accumulate in order", func(t *testing.T) {
+		var calls []string
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(2),
+			retry.WithClock(newFakeClock()),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				calls = append(calls, "log")
+			}),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				calls = append(calls, "metrics")
+			}),
+			retry.OnExhausted(func(ctx context.Context, attempts int, err error) {
+				calls = append(calls, "exhausted 1")
+			}),
+			retry.OnExhausted(func(ctx context.Context, attempts int, err error) {
+				calls = append(calls, "exhausted 2")
+			}),
+		)
+
+		want := []string{"log", "metrics", "exhausted 1", "exhausted 2"}
+		if len(calls) != len(want) {
+			t.Fatalf("expected %v, got %v", want, calls)
+		}
+		for i := range want {
+			if calls[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, calls)
+			}
+		}
+	})
+
+	t.Run("ReplaceHooks discards earlier hooks", func(t *testing.T) {
+		var calls []string
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		},
+			retry.WithClock(newFakeClock()),
+			retry.OnSuccess(func(ctx context.Context, attempts int) {
+				calls = append(calls, "before")
+			}),
+			retry.ReplaceHooks(),
+			retry.OnSuccess(func(ctx context.Context, attempts int) {
+				calls = append(calls, "after")
+			}),
+		)
+
+		if len(calls) != 1 || calls[0] != "after" {
+			t.Fatalf("expected [after], got %v", calls)
+		}
+	})
+
+	t.Run("panicking hook does not stop the loop", func(t *testing.T) {
+		attempts := 0
+		var after []int
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errTest
+			}
+			return nil
+		},
+			retry.WithClock(newFakeClock()),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				panic("buggy hook")
+			}),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				after = append(after, attempt)
+			}),
+			retry.OnSuccess(func(ctx context.Context, attempts int) {
+				panic("buggy hook")
+			}),
+		)
+
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if len(after) != 2 {
+			t.Fatalf("expected later hook to run twice, got %v", after)
+		}
+	})
+
+	t.Run("panics are reported to OnHookPanic", func(t *testing.T) {
+		var recovered []any
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		},
+			retry.OnSuccess(func(ctx context.Context, attempts int) {
+				panic("buggy hook")
+			}),
+			retry.OnHookPanic(func(ctx context.Context, r any) {
+				panic("buggy panic hook")
+			}),
+			retry.OnHookPanic(func(ctx context.Context, r any) {
+				recovered = append(recovered, r)
+			}),
+		)
+
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if len(recovered) != 1 || recovered[0] != "buggy hook" {
+			t.Fatalf("expected the panic to be reported once, got %v", recovered)
+		}
+	})
+
+	t.Run("BeforeRetry hooks chain delays", func(t *testing.T) {
+		clock := newFakeClock()
+		var seen time.Duration
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(2),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(clock),
+			retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+				return retry.ProceedAfter(2 * delay)
+			}),
+			retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+				seen = delay
+				return retry.Proceed()
+			}),
+		)
+
+		if seen != 2*time.Second {
+			t.Fatalf("expected second hook to see 2s, got %v", seen)
+		}
+		if len(clock.sleeps) != 1 || clock.sleeps[0] != 2*time.Second {
+			t.Fatalf("expected sleeps [2s], got %v", clock.sleeps)
+		}
+	})
+
+	t.Run("panicking BeforeRetry proceeds", func(t *testing.T) {
+		attempts := 0
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errTest
+		},
+			retry.WithMaxAttempts(3),
+			retry.WithClock(newFakeClock()),
+			retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+				panic("buggy hook")
+			}),
+		)
+
+		if attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("wraps every attempt in order", func(t *testing.T) {
+		var calls []string
+		trace := func(name string) retry.Middleware {
+			return func(next retry.Func) retry.Func {
+				return func(ctx context.Context) error {
+					calls = append(calls, name+" in")
+					err := next(ctx)
+					calls = append(calls, name+" out")
+					return err
+				}
+			}
+		}
+
+		attempts := 0
+		policy := retry.New(
+			retry.WithClock(newFakeClock()),
+			retry.WithMiddleware(trace("policy")),
+		)
+		_ = policy.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			calls = append(calls, "fn")
+			if attempts < 2 {
+				return errTest
+			}
+			return nil
+		}, retry.WithMiddleware(trace("a"), trace("b")))
+
+		want := []string{
+			"policy in", "a in", "b in", "fn", "b out", "a out", "policy out",
+			"policy in", "a in", "b in", "fn", "b out", "a out", "policy out",
+		}
+		if len(calls) != len(want) {
+			t.Fatalf("expected %v, got %v", want, calls)
+		}
+		for i := range want {
+			if calls[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, calls)
+			}
+		}
+	})
+
+	t.Run("exposes attempt metadata", func(t *testing.T) {
+		clock := newFakeClock()
+		var infos []retry.AttemptInfo
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(3),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(clock),
+			retry.WithMiddleware(func(next retry.Func) retry.Func {
+				return func(ctx context.Context) error {
+					info, ok := retry.AttemptFromContext(ctx)
+					if !ok {
+						t.Fatal("expected attempt info in context")
+					}
+					infos = append(infos, info)
+					return next(ctx)
+				}
+			}),
+		)
+
+		if len(infos) != 3 {
+			t.Fatalf("expected 3 attempts, got %d", len(infos))
+		}
+		if infos[0].Attempt != 1 || infos[0].LastErr != nil || infos[0].Elapsed != 0 {
+			t.Fatalf("unexpected first attempt info: %+v", infos[0])
+		}
+		if infos[2].Attempt != 3 || infos[2].MaxAttempts != 3 || !errors.Is(infos[2].LastErr, errTest) {
+			t.Fatalf("unexpected third attempt info: %+v", infos[2])
+		}
+		if infos[2].Elapsed != 2*time.Second {
+			t.Fatalf("expected 2s elapsed, got %v", infos[2].Elapsed)
+		}
+	})
+
+	t.Run("can stop the loop", func(t *testing.T) {
+		attempts := 0
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errTest
+		},
+			retry.WithMaxAttempts(5),
+			retry.WithClock(newFakeClock()),
+			retry.WithMiddleware(func(next retry.Func) retry.Func {
+				return func(ctx context.Context) error {
+					return retry.Stop(next(ctx))
+				}
+			}),
+		)
+
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected errTest, got %v", err)
+		}
+		if attempts != 1 {
+			t.Fatalf("expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("no attempt info without middleware", func(t *testing.T) {
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			if _, ok := retry.AttemptFromContext(ctx); ok {
+				t.Fatal("expected no attempt info")
+			}
+			return nil
+		})
+	})
+}
+
 func TestMaxDuration(t *testing.T) {
 	t.Run("stops when duration exceeded", func(t *testing.T) {
 		clock := newFakeClock()
@@ -490,41 +897,227 @@ func TestDefault(t *testing.T) {
 	})
 }
 
-func TestMaxDurationEdgeCases(t *testing.T) {
-	t.Run("delay exceeds remaining time budget", func(t *testing.T) {
+func TestSetDefault(t *testing.T) {
+	t.Run("package Do uses Default policy initially", func(t *testing.T) {
 		clock := newFakeClock()
-		attempts := 0
-		var exhaustedCalled bool
-
-		err := retry.Do(context.Background(), func(ctx context.Context) error {
-			attempts++
-			// Advance clock so remaining time is less than backoff delay
-			clock.Advance(90 * time.Millisecond)
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
 			return errTest
-		},
-			retry.WithMaxAttempts(10),
-			retry.WithMaxDuration(100*time.Millisecond),
-			retry.WithBackoff(retry.Constant(50*time.Millisecond)),
-			retry.WithClock(clock),
-			retry.OnExhausted(func(ctx context.Context, a int, e error) {
-				exhaustedCalled = true
-			}),
-		)
+		}, retry.WithClock(clock))
 
-		if !errors.Is(err, errTest) {
-			t.Fatalf("expected errTest, got %v", err)
+		// Default applies ±20% jitter to 100ms and 200ms.
+		if len(clock.sleeps) != 2 {
+			t.Fatalf("expected 2 sleeps, got %v", clock.sleeps)
 		}
-		if !exhaustedCalled {
-			t.Fatal("expected OnExhausted to be called")
+		if d := clock.sleeps[1]; d < 160*time.Millisecond || d > 240*time.Millisecond {
+			t.Fatalf("expected second sleep within 200ms ±20%%, got %v", d)
 		}
 	})
 
-	t.Run("delay capped to remaining budget", func(t *testing.T) {
-		clock := newFakeClock()
-		var delays []time.Duration
+	t.Run("package Do uses the configured default", func(t *testing.T) {
+		prev := retry.DefaultPolicy()
+		t.Cleanup(func() { retry.SetDefault(prev) })
 
-		err := retry.Do(context.Background(), func(ctx context.Context) error {
-			clock.Advance(80 * time.Millisecond)
+		policy := retry.New(retry.WithMaxAttempts(5), retry.WithClock(newFakeClock()))
+		retry.SetDefault(policy)
+		if retry.DefaultPolicy() != policy {
+			t.Fatal("expected DefaultPolicy to return the configured policy")
+		}
+
+		attempts := 0
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errTest
+		})
+		if attempts != 5 {
+			t.Fatalf("expected 5 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("nil restores Default", func(t *testing.T) {
+		prev := retry.DefaultPolicy()
+		t.Cleanup(func() { retry.SetDefault(prev) })
+
+		retry.SetDefault(retry.Never())
+		retry.SetDefault(nil)
+
+		attempts := 0
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errTest
+		}, retry.WithClock(newFakeClock()))
+		if attempts != retry.DefaultMaxAttempts {
+			t.Fatalf("expected %d attempts, got %d", retry.DefaultMaxAttempts, attempts)
+		}
+	})
+
+	t.Run("safe for concurrent use", func(t *testing.T) {
+		prev := retry.DefaultPolicy()
+		t.Cleanup(func() { retry.SetDefault(prev) })
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Go(func() {
+				retry.SetDefault(retry.New(retry.WithClock(newFakeClock())))
+			})
+			wg.Go(func() {
+				_ = retry.Do(context.Background(), func(ctx context.Context) error {
+					return nil
+				})
+			})
+		}
+		wg.Wait()
+	})
+}
+
+func TestContextPolicy(t *testing.T) {
+	t.Run("DoCtx uses the context policy", func(t *testing.T) {
+		ctx := retry.WithPolicy(context.Background(), retry.Never())
+
+		attempts := 0
+		_ = retry.DoCtx(ctx, func(ctx context.Context) error {
+			attempts++
+			return errTest
+		}, retry.WithClock(newFakeClock()))
+
+		if attempts != 1 {
+			t.Fatalf("expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("DoCtx falls back to the default policy", func(t *testing.T) {
+		attempts := 0
+		_ = retry.DoCtx(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errTest
+		}, retry.WithClock(newFakeClock()))
+
+		if attempts != retry.DefaultMaxAttempts {
+			t.Fatalf("expected %d attempts, got %d", retry.DefaultMaxAttempts, attempts)
+		}
+	})
+
+	t.Run("PolicyFromContext", func(t *testing.T) {
+		if retry.PolicyFromContext(context.Background()) != retry.DefaultPolicy() {
+			t.Fatal("expected default policy for empty context")
+		}
+		if retry.PolicyFromContext(retry.WithPolicy(context.Background(), nil)) != retry.DefaultPolicy() {
+			t.Fatal("expected default policy for nil context policy")
+		}
+
+		policy := retry.New()
+		ctx := retry.WithPolicy(context.Background(), policy)
+		if retry.PolicyFromContext(ctx) != policy {
+			t.Fatal("expected context policy")
+		}
+	})
+}
+
+func TestNoRetry(t *testing.T) {
+	t.Run("runs fn once and ignores options", func(t *testing.T) {
+		attempts := 0
+		err := retry.NoRetry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errTest
+		}, retry.WithMaxAttempts(5))
+
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected errTest, got %v", err)
+		}
+		if attempts != 1 {
+			t.Fatalf("expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("unwraps Stop", func(t *testing.T) {
+		err := retry.NoRetry.Do(context.Background(), func(ctx context.Context) error {
+			return retry.Stop(errTest)
+		})
+
+		if err != errTest {
+			t.Fatalf("expected unwrapped errTest, got %v", err)
+		}
+	})
+}
+
+func TestRetryable(t *testing.T) {
+	nonRetryable := errors.New("non-retryable")
+	policy := retry.New(retry.IfNot(func(err error) bool {
+		return errors.Is(err, nonRetryable)
+	}))
+
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"retryable", errTest, true},
+		{"rejected by condition", nonRetryable, false},
+		{"stopped", retry.Stop(errTest), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := policy.Retryable(tc.err); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNoCondition(t *testing.T) {
+	policy := retry.New()
+	if policy.Describe().Conditional {
+		t.Fatal("expected no If condition by default")
+	}
+	if !policy.Retryable(errTest) || policy.Retryable(nil) {
+		t.Fatal("expected every non-nil error to be retryable")
+	}
+
+	attempts := 0
+	_ = policy.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errTest
+	}, retry.WithClock(newFakeClock()))
+	if attempts != retry.DefaultMaxAttempts {
+		t.Fatalf("expected %d attempts, got %d", retry.DefaultMaxAttempts, attempts)
+	}
+}
+
+func TestMaxDurationEdgeCases(t *testing.T) {
+	t.Run("delay exceeds remaining time budget", func(t *testing.T) {
+		clock := newFakeClock()
+		attempts := 0
+		var exhaustedCalled bool
+
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			// Advance clock so remaining time is less than backoff delay
+			clock.Advance(90 * time.Millisecond)
+			return errTest
+		},
+			retry.WithMaxAttempts(10),
+			retry.WithMaxDuration(100*time.Millisecond),
+			retry.WithBackoff(retry.Constant(50*time.Millisecond)),
+			retry.WithClock(clock),
+			retry.OnExhausted(func(ctx context.Context, a int, e error) {
+				exhaustedCalled = true
+			}),
+		)
+
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected errTest, got %v", err)
+		}
+		if !exhaustedCalled {
+			t.Fatal("expected OnExhausted to be called")
+		}
+	})
+
+	t.Run("delay capped to remaining budget", func(t *testing.T) {
+		clock := newFakeClock()
+		var delays []time.Duration
+
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			clock.Advance(80 * time.Millisecond)
 			return errTest
 		},
 			retry.WithMaxAttempts(10),
@@ -600,6 +1193,33 @@ func TestMaxDurationEdgeCases(t *testing.T) {
 			t.Fatalf("expected at least 1 attempt, got %d", exhaustedAttempts)
 		}
 	})
+
+	t.Run("zero delay retries while budget remains", func(t *testing.T) {
+		clock := newFakeClock()
+		attempts := 0
+		var reason retry.StopReason
+
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			clock.Advance(10 * time.Millisecond)
+			return errTest
+		},
+			retry.WithMaxAttempts(3),
+			retry.WithMaxDuration(time.Second),
+			retry.WithBackoff(retry.Constant(0)),
+			retry.WithClock(clock),
+			retry.OnGiveUp(func(_ context.Context, _ int, _ error, r retry.StopReason) {
+				reason = r
+			}),
+		)
+
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected errTest, got %v", err)
+		}
+		if attempts != 3 || reason != retry.StopExhausted {
+			t.Fatalf("expected 3 attempts and exhausted, got %d and %v", attempts, reason)
+		}
+	})
 }
 
 func TestZeroMaxAttempts(t *testing.T) {
@@ -705,3 +1325,493 @@ func TestRealClock(t *testing.T) {
 		}
 	})
 }
+
+func TestOnError(t *testing.T) {
+	errUnauthorized := errors.New("unauthorized")
+	isUnauthorized := func(err error) bool { return errors.Is(err, errUnauthorized) }
+
+	t.Run("runs remediation when condition matches", func(t *testing.T) {
+		var remedied []error
+		attempts := 0
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return errUnauthorized
+			}
+			if attempts == 2 {
+				return errTest
+			}
+			return nil
+		},
+			retry.WithClock(newFakeClock()),
+			retry.OnError(isUnauthorized, func(ctx context.Context, err error) error {
+				remedied = append(remedied, err)
+				return nil
+			}),
+		)
+
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if len(remedied) != 1 || !errors.Is(remedied[0], errUnauthorized) {
+			t.Fatalf("expected one remediation for errUnauthorized, got %v", remedied)
+		}
+	})
+
+	t.Run("nil condition matches every error", func(t *testing.T) {
+		calls := 0
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(3),
+			retry.WithClock(newFakeClock()),
+			retry.OnError(nil, func(ctx context.Context, err error) error {
+				calls++
+				return nil
+			}),
+		)
+
+		if calls != 2 {
+			t.Fatalf("expected 2 remediations, got %d", calls)
+		}
+	})
+
+	t.Run("not run after the final attempt", func(t *testing.T) {
+		calls := 0
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(1),
+			retry.WithClock(newFakeClock()),
+			retry.OnError(nil, func(ctx context.Context, err error) error {
+				calls++
+				return nil
+			}),
+		)
+
+		if calls != 0 {
+			t.Fatalf("expected no remediation, got %d", calls)
+		}
+	})
+
+	t.Run("SkipDelay retries without sleeping", func(t *testing.T) {
+		clock := newFakeClock()
+		var delays []time.Duration
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errUnauthorized
+		},
+			retry.WithMaxAttempts(2),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(clock),
+			retry.OnError(isUnauthorized, func(ctx context.Context, err error) error {
+				return nil
+			}, retry.SkipDelay()),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				delays = append(delays, delay)
+			}),
+		)
+
+		if len(delays) != 1 || delays[0] != 0 {
+			t.Fatalf("expected a single zero delay, got %v", delays)
+		}
+	})
+
+	t.Run("SkipDelay ignored when remediation fails", func(t *testing.T) {
+		var delays []time.Duration
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(2),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(newFakeClock()),
+			retry.OnError(nil, func(ctx context.Context, err error) error {
+				return errors.New("refresh failed")
+			}, retry.SkipDelay()),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				delays = append(delays, delay)
+			}),
+		)
+
+		if len(delays) != 1 || delays[0] != time.Second {
+			t.Fatalf("expected a single 1s delay, got %v", delays)
+		}
+	})
+
+	t.Run("StopOnFailure returns both errors", func(t *testing.T) {
+		errRefresh := errors.New("refresh failed")
+		attempts := 0
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return errUnauthorized
+		},
+			retry.WithMaxAttempts(5),
+			retry.WithClock(newFakeClock()),
+			retry.OnError(isUnauthorized, func(ctx context.Context, err error) error {
+				return errRefresh
+			}, retry.StopOnFailure()),
+		)
+
+		if attempts != 1 {
+			t.Fatalf("expected 1 attempt, got %d", attempts)
+		}
+		if !errors.Is(err, errUnauthorized) {
+			t.Fatalf("expected err to contain errUnauthorized, got %v", err)
+		}
+		if !errors.Is(err, errRefresh) {
+			t.Fatalf("expected err to contain errRefresh, got %v", err)
+		}
+	})
+
+	t.Run("remediations run in order", func(t *testing.T) {
+		var order []string
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(2),
+			retry.WithClock(newFakeClock()),
+			retry.OnError(nil, func(ctx context.Context, err error) error {
+				order = append(order, "first")
+				return nil
+			}),
+			retry.OnError(nil, func(ctx context.Context, err error) error {
+				order = append(order, "second")
+				return nil
+			}),
+		)
+
+		if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+			t.Fatalf("expected [first second], got %v", order)
+		}
+	})
+}
+
+func TestBeforeRetry(t *testing.T) {
+	t.Run("Proceed keeps planned delay", func(t *testing.T) {
+		clock := newFakeClock()
+		var seen []time.Duration
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(3),
+			retry.WithBackoff(retry.Linear(10*time.Millisecond)),
+			retry.WithClock(clock),
+			retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+				seen = append(seen, delay)
+				return retry.Proceed()
+			}),
+		)
+
+		if len(seen) != 2 || seen[0] != 10*time.Millisecond || seen[1] != 20*time.Millisecond {
+			t.Fatalf("expected [10ms 20ms], got %v", seen)
+		}
+		if len(clock.sleeps) != 2 || clock.sleeps[1] != 20*time.Millisecond {
+			t.Fatalf("expected sleeps [10ms 20ms], got %v", clock.sleeps)
+		}
+	})
+
+	t.Run("ProceedAfter replaces delay", func(t *testing.T) {
+		clock := newFakeClock()
+		var retryDelay time.Duration
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(2),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(clock),
+			retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+				return retry.ProceedAfter(5 * time.Second)
+			}),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				retryDelay = delay
+			}),
+		)
+
+		if retryDelay != 5*time.Second {
+			t.Fatalf("expected OnRetry to see 5s, got %v", retryDelay)
+		}
+		if len(clock.sleeps) != 1 || clock.sleeps[0] != 5*time.Second {
+			t.Fatalf("expected sleeps [5s], got %v", clock.sleeps)
+		}
+	})
+
+	t.Run("ProceedAfter capped by time budget", func(t *testing.T) {
+		clock := newFakeClock()
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(2),
+			retry.WithMaxDuration(time.Second),
+			retry.WithBackoff(retry.Constant(time.Millisecond)),
+			retry.WithClock(clock),
+			retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+				return retry.ProceedAfter(time.Hour)
+			}),
+		)
+
+		if len(clock.sleeps) != 1 || clock.sleeps[0] != time.Second {
+			t.Fatalf("expected sleeps [1s], got %v", clock.sleeps)
+		}
+	})
+
+	t.Run("Cancel returns collected errors", func(t *testing.T) {
+		err1 := errors.New("error 1")
+		err2 := errors.New("error 2")
+		attempts := 0
+		var exhausted, retried bool
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return err1
+			}
+			return err2
+		},
+			retry.WithMaxAttempts(5),
+			retry.WithClock(newFakeClock()),
+			retry.WithAllErrors(),
+			retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+				if attempt == 2 {
+					return retry.Cancel()
+				}
+				return retry.Proceed()
+			}),
+			retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+				if attempt == 2 {
+					retried = true
+				}
+			}),
+			retry.OnExhausted(func(ctx context.Context, attempts int, err error) {
+				exhausted = true
+			}),
+		)
+
+		if attempts != 2 {
+			t.Fatalf("expected 2 attempts, got %d", attempts)
+		}
+		if !errors.Is(err, err1) || !errors.Is(err, err2) {
+			t.Fatalf("expected both errors, got %v", err)
+		}
+		if retried || exhausted {
+			t.Fatal("expected no OnRetry or OnExhausted after Cancel")
+		}
+	})
+
+	t.Run("Terminal returns current error alone", func(t *testing.T) {
+		err1 := errors.New("error 1")
+		attempts := 0
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return err1
+			}
+			return errTest
+		},
+			retry.WithMaxAttempts(5),
+			retry.WithClock(newFakeClock()),
+			retry.WithAllErrors(),
+			retry.BeforeRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) retry.Decision {
+				if errors.Is(err, errTest) {
+					return retry.Terminal()
+				}
+				return retry.Proceed()
+			}),
+		)
+
+		if attempts != 2 {
+			t.Fatalf("expected 2 attempts, got %d", attempts)
+		}
+		if !errors.Is(err, errTest) || errors.Is(err, err1) {
+			t.Fatalf("expected errTest alone, got %v", err)
+		}
+	})
+}
+
+func TestAttemptHooks(t *testing.T) {
+	t.Run("OnAttemptStart called for every attempt", func(t *testing.T) {
+		var started []int
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return errTest
+		},
+			retry.WithMaxAttempts(3),
+			retry.WithClock(newFakeClock()),
+			retry.OnAttemptStart(func(ctx context.Context, attempt int) {
+				started = append(started, attempt)
+			}),
+		)
+
+		if len(started) != 3 || started[0] != 1 || started[2] != 3 {
+			t.Fatalf("expected attempts [1 2 3], got %v", started)
+		}
+	})
+
+	t.Run("OnAttemptEnd reports error and duration", func(t *testing.T) {
+		clock := newFakeClock()
+		var durations []time.Duration
+		var errs []error
+		attempts := 0
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			clock.Advance(time.Duration(attempts) * 10 * time.Millisecond)
+			if attempts < 2 {
+				return errTest
+			}
+			return nil
+		},
+			retry.WithClock(clock),
+			retry.OnAttemptEnd(func(ctx context.Context, attempt int, err error, d time.Duration) {
+				errs = append(errs, err)
+				durations = append(durations, d)
+			}),
+		)
+
+		if len(durations) != 2 || durations[0] != 10*time.Millisecond || durations[1] != 20*time.Millisecond {
+			t.Fatalf("expected durations [10ms 20ms], got %v", durations)
+		}
+		if !errors.Is(errs[0], errTest) || errs[1] != nil {
+			t.Fatalf("expected errors [errTest <nil>], got %v", errs)
+		}
+	})
+}
+
+func TestOnGiveUp(t *testing.T) {
+	cases := []struct {
+		name   string
+		fn     retry.Func
+		opts   []retry.Option
+		reason retry.StopReason
+	}{
+		{
+			name:   "exhausted",
+			fn:     func(ctx context.Context) error { return errTest },
+			opts:   []retry.Option{retry.WithMaxAttempts(2)},
+			reason: retry.StopExhausted,
+		},
+		{
+			name:   "condition",
+			fn:     func(ctx context.Context) error { return errTest },
+			opts:   []retry.Option{retry.If(func(error) bool { return false })},
+			reason: retry.StopCondition,
+		},
+		{
+			name:   "stop",
+			fn:     func(ctx context.Context) error { return retry.Stop(errTest) },
+			reason: retry.StopTerminal,
+		},
+		{
+			name: "max duration",
+			fn:   func(ctx context.Context) error { return errTest },
+			opts: []retry.Option{
+				retry.WithMaxAttempts(100),
+				retry.WithMaxDuration(time.Second),
+				retry.WithBackoff(retry.Constant(400 * time.Millisecond)),
+			},
+			reason: retry.StopMaxDuration,
+		},
+		{
+			name: "canceled",
+			fn:   func(ctx context.Context) error { return errTest },
+			opts: []retry.Option{retry.BeforeRetry(func(context.Context, int, error, time.Duration) retry.Decision {
+				return retry.Cancel()
+			})},
+			reason: retry.StopCanceled,
+		},
+		{
+			name: "remedy",
+			fn:   func(ctx context.Context) error { return errTest },
+			opts: []retry.Option{retry.OnError(nil, func(context.Context, error) error {
+				return errors.New("remedy failed")
+			}, retry.StopOnFailure())},
+			reason: retry.StopRemedy,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			var reason retry.StopReason
+			var gaveUpErr error
+			opts := append([]retry.Option{retry.WithClock(newFakeClock())}, tc.opts...)
+			opts = append(opts, retry.OnGiveUp(func(ctx context.Context, attempts int, err error, r retry.StopReason) {
+				calls++
+				reason = r
+				gaveUpErr = err
+			}))
+
+			err := retry.Do(context.Background(), tc.fn, opts...)
+
+			if calls != 1 {
+				t.Fatalf("expected 1 OnGiveUp call, got %d", calls)
+			}
+			if reason != tc.reason {
+				t.Fatalf("expected reason %v, got %v", tc.reason, reason)
+			}
+			if gaveUpErr != err {
+				t.Fatalf("expected OnGiveUp to receive returned error %v, got %v", err, gaveUpErr)
+			}
+		})
+	}
+
+	t.Run("context done while sleeping", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var reason retry.StopReason
+		_ = retry.Do(ctx, func(ctx context.Context) error {
+			cancel()
+			return errTest
+		},
+			retry.WithClock(newFakeClock()),
+			retry.OnGiveUp(func(ctx context.Context, attempts int, err error, r retry.StopReason) {
+				reason = r
+			}),
+		)
+
+		if reason != retry.StopContext {
+			t.Fatalf("expected reason %v, got %v", retry.StopContext, reason)
+		}
+	})
+
+	t.Run("not called on success", func(t *testing.T) {
+		called := false
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		},
+			retry.WithClock(newFakeClock()),
+			retry.OnGiveUp(func(ctx context.Context, attempts int, err error, r retry.StopReason) {
+				called = true
+			}),
+		)
+
+		if called {
+			t.Fatal("expected OnGiveUp not to be called")
+		}
+	})
+}
+
+func TestStopReasonString(t *testing.T) {
+	if s := retry.StopMaxDuration.String(); s != "max_duration" {
+		t.Fatalf("expected max_duration, got %q", s)
+	}
+	if s := retry.StopShutdown.String(); s != "shutdown" {
+		t.Fatalf("expected shutdown, got %q", s)
+	}
+	if s := retry.StopReason(0).String(); s != "unknown" {
+		t.Fatalf("expected unknown, got %q", s)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	var order []string
+	combined := retry.Options(
+		retry.WithMaxAttempts(2),
+		retry.OnRetry(func(context.Context, int, error, time.Duration) { order = append(order, "first") }),
+		retry.OnRetry(func(context.Context, int, error, time.Duration) { order = append(order, "second") }),
+	)
+
+	calls := 0
+	_ = retry.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errTest
+	}, retry.WithClock(newFakeClock()), combined)
+
+	if calls != 2 || len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Fatalf("expected 2 calls and hooks in order, got %d calls and %v", calls, order)
+	}
+}
@@ -0,0 +1,32 @@
+package retryadmin_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retryadmin"
+)
+
+// ExampleHandler demonstrates disabling retries for one policy at runtime.
+func ExampleHandler() {
+	registry := retry.NewRegistry()
+	_ = registry.Load([]byte(`{"db": {"maxAttempts": 5}}`))
+
+	mux := http.NewServeMux()
+	mux.Handle("/admin/retry/", http.StripPrefix("/admin/retry", retryadmin.Handler(registry)))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/retry/db/disable", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	body, _ := io.ReadAll(rec.Body)
+	fmt.Print(string(body))
+	fmt.Println(registry.Get("db").Overrides().MaxAttempts)
+
+	// Output:
+	// {"name":"db","policy":"attempts=5 backoff=exponential(100ms)","overrides":{"maxAttempts":1}}
+	// 1
+}
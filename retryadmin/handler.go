@@ -0,0 +1,191 @@
+// Package retryadmin provides an HTTP handler for adjusting the live policies
+// of a retry.Registry at runtime, so operators can disable or throttle retries
+// during an incident without a deploy.
+//
+// Mount the handler under a prefix and behind whatever authentication guards
+// other admin endpoints:
+//
+//	mux.Handle("/admin/retry/", http.StripPrefix("/admin/retry", retryadmin.Handler(registry)))
+//
+// Routes, relative to the mount point:
+//
+//	GET    /                          list policies and their overrides
+//	GET    /{name}                    show one policy
+//	PUT    /{name}/overrides          replace overrides with a JSON retry.Overrides
+//	DELETE /{name}/overrides          clear overrides
+//	POST   /{name}/disable            set max attempts to 1
+//	POST   /{name}/scale?factor=F     scale delays by F (0 removes scaling)
+//	POST   /{name}/pause              hold retries
+//	POST   /{name}/resume             release held retries
+//
+// Every response is JSON. Changes are applied with Policy.SetOverrides, so
+// they take effect atomically, including for calls already running, and
+// survive registry reloads.
+//
+// InFlightHandler serves a /debug/retries page listing the calls currently
+// retrying, for policies that use retry.WithTracking.
+package retryadmin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/bjaus/retry"
+)
+
+// Status describes a policy in handler responses.
+type Status struct {
+	Name      string          `json:"name"`
+	Policy    string          `json:"policy"`
+	Overrides retry.Overrides `json:"overrides"`
+}
+
+type handler struct {
+	registry *retry.Registry
+	mu       sync.Mutex // serializes read-modify-write of overrides
+}
+
+// Handler returns an http.Handler exposing the policies in registry.
+func Handler(registry *retry.Registry) http.Handler {
+	h := &handler{registry: registry}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", h.list)
+	mux.HandleFunc("GET /{name}", h.show)
+	mux.HandleFunc("PUT /{name}/overrides", h.set)
+	mux.HandleFunc("DELETE /{name}/overrides", h.update(func(*retry.Overrides, *http.Request) error {
+		return nil
+	}, true))
+	mux.HandleFunc("POST /{name}/disable", h.update(func(o *retry.Overrides, _ *http.Request) error {
+		o.MaxAttempts = 1
+		return nil
+	}, false))
+	mux.HandleFunc("POST /{name}/scale", h.update(func(o *retry.Overrides, r *http.Request) error {
+		factor, err := strconv.ParseFloat(r.URL.Query().Get("factor"), 64)
+		if err != nil {
+			return errors.New("factor must be a number")
+		}
+		o.DelayScale = factor
+		return validate(*o)
+	}, false))
+	mux.HandleFunc("POST /{name}/pause", h.update(func(o *retry.Overrides, _ *http.Request) error {
+		o.Paused = true
+		return nil
+	}, false))
+	mux.HandleFunc("POST /{name}/resume", h.update(func(o *retry.Overrides, _ *http.Request) error {
+		o.Paused = false
+		return nil
+	}, false))
+	return mux
+}
+
+func (h *handler) list(w http.ResponseWriter, _ *http.Request) {
+	statuses := []Status{}
+	for _, name := range h.registry.Names() {
+		if p, ok := h.registry.Lookup(name); ok {
+			statuses = append(statuses, status(name, p))
+		}
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (h *handler) show(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	p, ok := h.lookup(w, name)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, status(name, p))
+}
+
+func (h *handler) set(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	p, ok := h.lookup(w, name)
+	if !ok {
+		return
+	}
+
+	var o retry.Overrides
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&o); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid overrides: %w", err))
+		return
+	}
+	if err := validate(o); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.mu.Lock()
+	p.SetOverrides(o)
+	h.mu.Unlock()
+	writeJSON(w, http.StatusOK, status(name, p))
+}
+
+// update returns a handler that edits the named policy's overrides, starting
+// from the current ones or, if reset is set, from none.
+func (h *handler) update(edit func(*retry.Overrides, *http.Request) error, reset bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		p, ok := h.lookup(w, name)
+		if !ok {
+			return
+		}
+
+		h.mu.Lock()
+		var o retry.Overrides
+		if !reset {
+			o = p.Overrides()
+		}
+		err := edit(&o, r)
+		if err == nil {
+			p.SetOverrides(o)
+		}
+		h.mu.Unlock()
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, status(name, p))
+	}
+}
+
+func (h *handler) lookup(w http.ResponseWriter, name string) (*retry.Policy, bool) {
+	p, ok := h.registry.Lookup(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no policy named %q", name))
+	}
+	return p, ok
+}
+
+var errMethod = errors.New("method not allowed")
+
+func validate(o retry.Overrides) error {
+	if o.MaxAttempts < 0 {
+		return fmt.Errorf("maxAttempts %d is negative", o.MaxAttempts)
+	}
+	if o.DelayScale < 0 || math.IsNaN(o.DelayScale) || math.IsInf(o.DelayScale, 0) {
+		return fmt.Errorf("delayScale %v must be a finite, non-negative number", o.DelayScale)
+	}
+	return nil
+}
+
+func status(name string, p *retry.Policy) Status {
+	return Status{Name: name, Policy: p.String(), Overrides: p.Overrides()}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
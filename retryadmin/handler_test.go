@@ -0,0 +1,158 @@
+package retryadmin_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retryadmin"
+)
+
+func newServer(t *testing.T) (*retry.Registry, *httptest.Server) {
+	t.Helper()
+	registry := retry.NewRegistry()
+	if err := registry.Load([]byte(`{
+		"db": {"maxAttempts": 5, "backoff": {"type": "constant", "delay": "0s"}},
+		"payments-api": {"maxAttempts": 3}
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(retryadmin.Handler(registry))
+	t.Cleanup(srv.Close)
+	return registry, srv
+}
+
+func request(t *testing.T, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, strings.TrimSpace(string(b))
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("lists policies", func(t *testing.T) {
+		_, srv := newServer(t)
+
+		code, body := request(t, http.MethodGet, srv.URL+"/", "")
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", code, body)
+		}
+		var statuses []retryadmin.Status
+		if err := json.Unmarshal([]byte(body), &statuses); err != nil {
+			t.Fatal(err)
+		}
+		if len(statuses) != 2 || statuses[0].Name != "db" || statuses[0].Policy != "attempts=5 backoff=constant(0s)" {
+			t.Fatalf("unexpected statuses %+v", statuses)
+		}
+	})
+
+	t.Run("disable sets max attempts to 1", func(t *testing.T) {
+		registry, srv := newServer(t)
+
+		code, body := request(t, http.MethodPost, srv.URL+"/db/disable", "")
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", code, body)
+		}
+
+		calls := 0
+		_ = registry.Do(context.Background(), "db", func(context.Context) error {
+			calls++
+			return errors.New("unavailable")
+		})
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got %d", calls)
+		}
+		if registry.Get("payments-api").Overrides() != (retry.Overrides{}) {
+			t.Fatal("expected other policies to be unchanged")
+		}
+	})
+
+	t.Run("shortcuts combine", func(t *testing.T) {
+		registry, srv := newServer(t)
+
+		request(t, http.MethodPost, srv.URL+"/db/scale?factor=2", "")
+		request(t, http.MethodPost, srv.URL+"/db/pause", "")
+		code, body := request(t, http.MethodGet, srv.URL+"/db", "")
+		if code != http.StatusOK || !strings.Contains(body, `"overrides":{"delayScale":2,"paused":true}`) {
+			t.Fatalf("unexpected response %d: %s", code, body)
+		}
+
+		request(t, http.MethodPost, srv.URL+"/db/resume", "")
+		if got := registry.Get("db").Overrides(); got != (retry.Overrides{DelayScale: 2}) {
+			t.Fatalf("unexpected overrides %+v", got)
+		}
+
+		request(t, http.MethodDelete, srv.URL+"/db/overrides", "")
+		if got := registry.Get("db").Overrides(); got != (retry.Overrides{}) {
+			t.Fatalf("expected overrides to be cleared, got %+v", got)
+		}
+	})
+
+	t.Run("put replaces overrides", func(t *testing.T) {
+		registry, srv := newServer(t)
+		registry.Get("db").SetOverrides(retry.Overrides{Paused: true})
+
+		code, body := request(t, http.MethodPut, srv.URL+"/db/overrides", `{"maxAttempts": 2}`)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", code, body)
+		}
+		if got := registry.Get("db").Overrides(); got != (retry.Overrides{MaxAttempts: 2}) {
+			t.Fatalf("unexpected overrides %+v", got)
+		}
+	})
+
+	t.Run("rejects bad requests", func(t *testing.T) {
+		registry, srv := newServer(t)
+
+		for _, tc := range []struct {
+			method, path, body string
+			code               int
+		}{
+			{http.MethodGet, "/missing", "", http.StatusNotFound},
+			{http.MethodPost, "/missing/disable", "", http.StatusNotFound},
+			{http.MethodPost, "/db/scale?factor=fast", "", http.StatusBadRequest},
+			{http.MethodPost, "/db/scale?factor=-1", "", http.StatusBadRequest},
+			{http.MethodPut, "/db/overrides", `{"maxAttempts": -1}`, http.StatusBadRequest},
+			{http.MethodPut, "/db/overrides", `{"retries": 0}`, http.StatusBadRequest},
+			{http.MethodPut, "/db/overrides", `not json`, http.StatusBadRequest},
+			{http.MethodGet, "/db/disable", "", http.StatusMethodNotAllowed},
+		} {
+			code, body := request(t, tc.method, srv.URL+tc.path, tc.body)
+			if code != tc.code {
+				t.Errorf("%s %s: expected %d, got %d: %s", tc.method, tc.path, tc.code, code, body)
+			}
+		}
+		if got := registry.Get("db").Overrides(); got != (retry.Overrides{}) {
+			t.Fatalf("expected no overrides after rejected requests, got %+v", got)
+		}
+	})
+
+	t.Run("overrides survive reloads", func(t *testing.T) {
+		registry, srv := newServer(t)
+
+		request(t, http.MethodPost, srv.URL+"/db/disable", "")
+		if err := registry.Load([]byte(`{"db": {"maxAttempts": 7}}`)); err != nil {
+			t.Fatal(err)
+		}
+		if got := registry.Get("db").Overrides(); got != (retry.Overrides{MaxAttempts: 1}) {
+			t.Fatalf("expected overrides to carry over, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package retryadmin
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+// Source reports tracked calls in flight. *retry.Policy and *retry.Registry
+// implement it.
+type Source interface {
+	InFlight() []retry.InFlightCall
+}
+
+// Call is the JSON form of a retry.InFlightCall.
+type Call struct {
+	Name      string     `json:"name"`
+	Attempt   int        `json:"attempt"`
+	LastError string     `json:"lastError,omitempty"`
+	NextWake  *time.Time `json:"nextWake,omitempty"`
+	Paused    bool       `json:"paused,omitempty"`
+	Started   time.Time  `json:"started"`
+	Elapsed   string     `json:"elapsed"`
+}
+
+// InFlightHandler returns an http.Handler listing the calls in flight in the
+// given sources, typically mounted at /debug/retries:
+//
+//	http.Handle("/debug/retries", retryadmin.InFlightHandler(registry))
+//
+// It renders an HTML table, or a JSON array of Call when the request has
+// ?format=json or accepts application/json. Only calls made with
+// retry.WithTracking are listed.
+func InFlightHandler(sources ...Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, errMethod)
+			return
+		}
+
+		var inflight []retry.InFlightCall
+		for _, src := range sources {
+			inflight = append(inflight, src.InFlight()...)
+		}
+		slices.SortFunc(inflight, func(a, b retry.InFlightCall) int { return a.Started.Compare(b.Started) })
+
+		calls := make([]Call, 0, len(inflight))
+		for _, c := range inflight {
+			calls = append(calls, toCall(c))
+		}
+
+		if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			writeJSON(w, http.StatusOK, calls)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = inflightPage.Execute(w, calls)
+	})
+}
+
+func toCall(c retry.InFlightCall) Call {
+	call := Call{
+		Name:    c.Name,
+		Attempt: c.Attempt,
+		Paused:  c.Paused,
+		Started: c.Started,
+		Elapsed: c.Elapsed.String(),
+	}
+	if c.LastErr != nil {
+		call.LastError = c.LastErr.Error()
+	}
+	if !c.NextWake.IsZero() {
+		call.NextWake = &c.NextWake
+	}
+	return call
+}
+
+var inflightPage = template.Must(template.New("inflight").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Retries in flight</title></head>
+<body>
+<h1>Retries in flight ({{len .}})</h1>
+<table border="1" cellpadding="4">
+<tr><th>Name</th><th>Attempt</th><th>Last error</th><th>Next wake-up</th><th>Elapsed</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Attempt}}</td><td>{{.LastError}}</td><td>{{if .Paused}}paused{{else if .NextWake}}{{.NextWake.Format "15:04:05.000"}}{{else}}running{{end}}</td><td>{{.Elapsed}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
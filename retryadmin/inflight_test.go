@@ -0,0 +1,122 @@
+package retryadmin_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retryadmin"
+)
+
+// blockingClock reads the real time, but its Sleep reports each wait on
+// sleeping and then blocks until ctx is done.
+type blockingClock struct {
+	sleeping chan time.Duration
+}
+
+func (c *blockingClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c *blockingClock) Sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case c.sleeping <- d:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestInFlightHandler(t *testing.T) {
+	clock := &blockingClock{sleeping: make(chan time.Duration)}
+	registry := retry.NewRegistry()
+	if err := registry.Register("db", retry.New(
+		retry.WithTracking("db.query"),
+		retry.WithBackoff(retry.Constant(time.Hour)),
+		retry.WithClock(clock),
+	)); err != nil {
+		t.Fatal(err)
+	}
+	standalone := retry.New(
+		retry.WithTracking("cache <get>"),
+		retry.WithBackoff(retry.Constant(time.Hour)),
+		retry.WithClock(clock),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{}, 2)
+	for _, p := range []*retry.Policy{registry.Get("db"), standalone} {
+		go func() {
+			_ = p.Do(ctx, func(context.Context) error { return errors.New("connection <refused>") })
+			done <- struct{}{}
+		}()
+	}
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		<-done
+	})
+
+	h := retryadmin.InFlightHandler(registry, standalone)
+	get := func(target, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	<-clock.sleeping
+	<-clock.sleeping
+
+	var calls []retryadmin.Call
+	if err := json.Unmarshal(get("/debug/retries?format=json", "").Body.Bytes(), &calls); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %+v", calls)
+	}
+	for _, c := range calls {
+		if c.NextWake == nil || c.Attempt != 1 || c.LastError != "connection <refused>" {
+			t.Errorf("unexpected call %+v", c)
+		}
+	}
+
+	t.Run("json via accept header", func(t *testing.T) {
+		rec := get("/debug/retries", "application/json")
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("unexpected content type %q", ct)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		rec := get("/debug/retries", "text/html")
+		body := rec.Body.String()
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+			t.Fatalf("unexpected content type %q", rec.Header().Get("Content-Type"))
+		}
+		for _, want := range []string{"Retries in flight (2)", "db.query", "cache &lt;get&gt;", "connection &lt;refused&gt;"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected %q in:\n%s", want, body)
+			}
+		}
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/retries", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("unexpected status %d", rec.Code)
+		}
+	})
+}
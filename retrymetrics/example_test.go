@@ -0,0 +1,38 @@
+package retrymetrics_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retrymetrics"
+)
+
+// ExampleCollector demonstrates recording metrics for a named operation.
+func ExampleCollector() {
+	metrics := retrymetrics.NewCollector()
+	// In a server: metrics.Publish("retry") and http.Handle("/metrics", metrics.Handler())
+
+	policy := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+		metrics.Instrument("inventory.reserve"),
+	)
+
+	attempts := 0
+	_ = policy.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	s := metrics.Snapshot()["inventory.reserve"]
+	fmt.Println("calls:", s.Calls, "attempts:", s.Attempts, "retries:", s.Retries, "successes:", s.Successes)
+
+	// Output:
+	// calls: 1 attempts: 2 retries: 1 successes: 1
+}
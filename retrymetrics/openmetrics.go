@@ -0,0 +1,106 @@
+package retrymetrics
+
+import (
+	"bufio"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of the OpenMetrics text format.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Handler returns an http.Handler serving the metrics in the OpenMetrics text
+// format, for Prometheus and compatible scrapers.
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		bw := bufio.NewWriter(w)
+		c.writeOpenMetrics(bw)
+		_ = bw.Flush()
+	})
+}
+
+func (c *Collector) writeOpenMetrics(w *bufio.Writer) {
+	ops := c.operations()
+	names := sortedNames(ops)
+	snaps := make([]Snapshot, len(names))
+	for i, name := range names {
+		snaps[i] = ops[name].snapshot()
+	}
+
+	counter := func(family, help string, value func(Snapshot) uint64) {
+		header(w, family, "counter", help)
+		for i, name := range names {
+			sample(w, family+"_total", labels(ops[name].pairs...), strconv.FormatUint(value(snaps[i]), 10))
+		}
+	}
+	counter("retry_calls", "Calls made through the retry loop.", func(s Snapshot) uint64 { return s.Calls })
+	counter("retry_attempts", "Attempts made, including first attempts.", func(s Snapshot) uint64 { return s.Attempts })
+	counter("retry_retries", "Retries scheduled after a failed attempt.", func(s Snapshot) uint64 { return s.Retries })
+	counter("retry_successes", "Calls that succeeded.", func(s Snapshot) uint64 { return s.Successes })
+
+	header(w, "retry_gave_up", "counter", "Calls that stopped without succeeding, by stop reason.")
+	for i, name := range names {
+		for _, r := range reasons[1:] {
+			reason := r.String()
+			sample(w, "retry_gave_up_total", labels(append(ops[name].pairs, "reason", reason)...),
+				strconv.FormatUint(snaps[i].GaveUp[reason], 10))
+		}
+	}
+
+	histogram := func(family, help string, value func(Snapshot) Histogram) {
+		header(w, family, "histogram", help)
+		for i, name := range names {
+			h := value(snaps[i])
+			var cumulative uint64
+			for j, count := range h.Counts {
+				cumulative += count
+				le := "+Inf"
+				if j < len(h.Bounds) {
+					le = formatFloat(h.Bounds[j])
+				}
+				sample(w, family+"_bucket", labels(append(ops[name].pairs, "le", le)...), strconv.FormatUint(cumulative, 10))
+			}
+			sample(w, family+"_sum", labels(ops[name].pairs...), formatFloat(h.Sum))
+			sample(w, family+"_count", labels(ops[name].pairs...), strconv.FormatUint(h.Count, 10))
+		}
+	}
+	histogram("retry_attempts_per_call", "Attempts made by finished calls.", func(s Snapshot) Histogram { return s.AttemptsPerCall })
+	histogram("retry_call_duration_seconds", "Duration of finished calls, including sleeps.", func(s Snapshot) Histogram { return s.CallDuration })
+	histogram("retry_sleep_seconds", "Delays slept before retrying.", func(s Snapshot) Histogram { return s.Sleep })
+
+	w.WriteString("# EOF\n")
+}
+
+func header(w *bufio.Writer, family, typ, help string) {
+	w.WriteString("# TYPE " + family + " " + typ + "\n")
+	w.WriteString("# HELP " + family + " " + help + "\n")
+}
+
+func sample(w *bufio.Writer, name, labels, value string) {
+	w.WriteString(name + labels + " " + value + "\n")
+}
+
+// labels formats name/value pairs as an OpenMetrics label set.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,314 @@
+// Package retrymetrics records retry metrics without a metrics library and
+// exposes them through expvar and an OpenMetrics text endpoint.
+//
+// A Collector keeps, per named operation: calls, attempts, retries,
+// successes and give-ups per stop reason, plus histograms of attempts per
+// call, call latency and retry sleeps. Instrument returns the retry.Option
+// that feeds it:
+//
+//	metrics := retrymetrics.NewCollector()
+//	metrics.Publish("retry") // expvar, under /debug/vars
+//	http.Handle("/metrics", metrics.Handler())
+//
+//	policy := retry.New(metrics.Instrument("db"))
+//
+// The OpenMetrics families are retry_calls, retry_attempts, retry_retries,
+// retry_successes and retry_gave_up (counters, labeled with operation and,
+// for retry_gave_up, reason), and retry_attempts_per_call,
+// retry_call_duration_seconds and retry_sleep_seconds (histograms).
+//
+// Calls made with retry.WithLabels are recorded in a separate series per
+// label set, and the labels are added to the OpenMetrics samples. Label
+// names are sanitized to [a-zA-Z0-9_], and labels named operation, reason
+// or le are dropped. Keep label values low-cardinality: each distinct set
+// is kept for the life of the Collector.
+package retrymetrics
+
+import (
+	"context"
+	"expvar"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+// Default histogram bucket upper bounds.
+var (
+	AttemptBuckets  = []float64{1, 2, 3, 5, 10}
+	DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+)
+
+// reasons lists every stop reason, indexed by its value.
+var reasons = func() []retry.StopReason {
+	rs := []retry.StopReason{0}
+	for r := retry.StopExhausted; r.String() != "unknown"; r++ {
+		rs = append(rs, r)
+	}
+	return rs
+}()
+
+// Collector records metrics for any number of named operations.
+// It is safe for concurrent use.
+type Collector struct {
+	mu  sync.Mutex
+	ops map[string]*operation // by series key
+}
+
+// NewCollector creates an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{ops: map[string]*operation{}}
+}
+
+// operation holds the metrics of one named operation and label set.
+type operation struct {
+	name   string
+	labels map[string]string // sanitized
+	pairs  []string          // operation and labels as OpenMetrics label pairs
+
+	calls     atomic.Uint64
+	attempts  atomic.Uint64
+	retries   atomic.Uint64
+	successes atomic.Uint64
+	gaveUp    []atomic.Uint64 // indexed by StopReason
+
+	attemptsPerCall *histogram
+	callDuration    *histogram
+	sleep           *histogram
+}
+
+// operation returns the series for name and labels, creating it if needed.
+func (c *Collector) operation(name string, labels map[string]string) *operation {
+	labels = sanitizeLabels(labels)
+	key := retry.Operation{Name: name, Labels: labels}.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	op, ok := c.ops[key]
+	if !ok {
+		pairs := []string{"operation", name}
+		for _, k := range slices.Sorted(maps.Keys(labels)) {
+			pairs = append(pairs, k, labels[k])
+		}
+		op = &operation{
+			name:            name,
+			labels:          labels,
+			pairs:           slices.Clip(pairs),
+			gaveUp:          make([]atomic.Uint64, len(reasons)),
+			attemptsPerCall: newHistogram(AttemptBuckets),
+			callDuration:    newHistogram(DurationBuckets),
+			sleep:           newHistogram(DurationBuckets),
+		}
+		c.ops[key] = op
+	}
+	return op
+}
+
+// sanitizeLabels returns labels with names made valid for OpenMetrics and
+// reserved names dropped, or nil if none remain.
+func sanitizeLabels(labels map[string]string) map[string]string {
+	var clean map[string]string
+	for k, v := range labels {
+		k = sanitizeLabelName(k)
+		switch k {
+		case "", "operation", "reason", "le":
+			continue
+		}
+		if clean == nil {
+			clean = make(map[string]string, len(labels))
+		}
+		clean[k] = v
+	}
+	return clean
+}
+
+func sanitizeLabelName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	if name != "" && '0' <= name[0] && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// Instrument returns a retry.Option that records every call it is applied to
+// under name, or under the call's retry.WithName name if name is empty.
+// Calls with retry.WithLabels are recorded per label set. Use it on a policy
+// or at a call site; calls to Instrument with the same name share metrics.
+func (c *Collector) Instrument(name string) retry.Option {
+	var static *operation
+	if name != "" {
+		static = c.operation(name, nil)
+	}
+
+	// series returns the operation of the call running ctx.
+	series := func(ctx context.Context) *operation {
+		info, _ := retry.OperationFromContext(ctx)
+		if static != nil && len(info.Labels) == 0 {
+			return static
+		}
+		if name != "" {
+			info.Name = name
+		}
+		return c.operation(info.Name, info.Labels)
+	}
+
+	finish := func(ctx context.Context, op *operation, attempts int) {
+		op.attemptsPerCall.observe(float64(attempts))
+		call, _ := retry.CallFromContext(ctx)
+		op.callDuration.observe(call.Elapsed().Seconds())
+	}
+
+	return retry.Options(
+		retry.OnAttemptStart(func(ctx context.Context, attempt int) {
+			op := series(ctx)
+			if attempt == 1 {
+				op.calls.Add(1)
+			}
+			op.attempts.Add(1)
+		}),
+		retry.OnRetry(func(ctx context.Context, _ int, _ error, delay time.Duration) {
+			op := series(ctx)
+			op.retries.Add(1)
+			op.sleep.observe(delay.Seconds())
+		}),
+		retry.OnSuccess(func(ctx context.Context, attempts int) {
+			op := series(ctx)
+			op.successes.Add(1)
+			finish(ctx, op, attempts)
+		}),
+		retry.OnGiveUp(func(ctx context.Context, attempts int, _ error, reason retry.StopReason) {
+			op := series(ctx)
+			if int(reason) < len(op.gaveUp) {
+				op.gaveUp[reason].Add(1)
+			}
+			finish(ctx, op, attempts)
+		}),
+	)
+}
+
+// Snapshot is a point-in-time copy of one operation's metrics.
+type Snapshot struct {
+	Operation       string            `json:"operation"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Calls           uint64            `json:"calls"`
+	Attempts        uint64            `json:"attempts"`
+	Retries         uint64            `json:"retries"`
+	Successes       uint64            `json:"successes"`
+	GaveUp          map[string]uint64 `json:"gaveUp"` // by stop reason
+	AttemptsPerCall Histogram         `json:"attemptsPerCall"`
+	CallDuration    Histogram         `json:"callDurationSeconds"`
+	Sleep           Histogram         `json:"sleepSeconds"`
+}
+
+// Histogram is a point-in-time copy of a histogram. Counts[i] is the number
+// of observations at most Bounds[i]; the last count, one past the bounds,
+// covers everything else. Counts are not cumulative.
+type Histogram struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []uint64  `json:"counts"`
+	Sum    float64   `json:"sum"`
+	Count  uint64    `json:"count"`
+}
+
+// Snapshot returns the metrics of every operation, keyed by name. Series
+// with labels are keyed as retry.Operation.String formats them, as in
+// "db [region=us-east-1]".
+func (c *Collector) Snapshot() map[string]Snapshot {
+	snaps := make(map[string]Snapshot)
+	for name, op := range c.operations() {
+		snaps[name] = op.snapshot()
+	}
+	return snaps
+}
+
+// Publish exposes Snapshot as the expvar variable name, served by the expvar
+// package at /debug/vars. Like expvar.Publish, it can be called only once per
+// name in a process, and panics if name is already taken.
+func (c *Collector) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return c.Snapshot() }))
+}
+
+func (c *Collector) operations() map[string]*operation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ops := make(map[string]*operation, len(c.ops))
+	for name, op := range c.ops {
+		ops[name] = op
+	}
+	return ops
+}
+
+func sortedNames(ops map[string]*operation) []string {
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (op *operation) snapshot() Snapshot {
+	s := Snapshot{
+		Operation:       op.name,
+		Labels:          op.labels,
+		Calls:           op.calls.Load(),
+		Attempts:        op.attempts.Load(),
+		Retries:         op.retries.Load(),
+		Successes:       op.successes.Load(),
+		GaveUp:          make(map[string]uint64, len(reasons)-1),
+		AttemptsPerCall: op.attemptsPerCall.snapshot(),
+		CallDuration:    op.callDuration.snapshot(),
+		Sleep:           op.sleep.snapshot(),
+	}
+	for _, r := range reasons[1:] {
+		s.GaveUp[r.String()] = op.gaveUp[r].Load()
+	}
+	return s
+}
+
+// histogram is a fixed-bucket histogram updated with atomics.
+type histogram struct {
+	bounds  []float64
+	counts  []atomic.Uint64 // len(bounds)+1
+	count   atomic.Uint64
+	sumBits atomic.Uint64 // float64 bits
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]atomic.Uint64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(v float64) {
+	i, _ := slices.BinarySearch(h.bounds, v)
+	h.counts[i].Add(1)
+	for {
+		old := h.sumBits.Load()
+		if h.sumBits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			break
+		}
+	}
+	h.count.Add(1)
+}
+
+func (h *histogram) snapshot() Histogram {
+	s := Histogram{
+		Bounds: h.bounds,
+		Counts: make([]uint64, len(h.counts)),
+		Sum:    math.Float64frombits(h.sumBits.Load()),
+		Count:  h.count.Load(),
+	}
+	for i := range h.counts {
+		s.Counts[i] = h.counts[i].Load()
+	}
+	return s
+}
@@ -0,0 +1,225 @@
+package retrymetrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retrymetrics"
+)
+
+var errTest = errors.New("test error")
+
+// fakeClock advances only when slept on or advanced.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(_ context.Context, d time.Duration) error {
+	c.Advance(d)
+	return nil
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// record runs one call per outcome script, each a list of attempt errors.
+func record(c *retrymetrics.Collector, operation string, scripts ...[]error) {
+	clock := &fakeClock{}
+	policy := retry.New(
+		retry.WithMaxAttempts(3),
+		retry.WithBackoff(retry.Constant(100*time.Millisecond)),
+		retry.WithClock(clock),
+		c.Instrument(operation),
+	)
+	for _, script := range scripts {
+		attempt := 0
+		_ = policy.Do(context.Background(), func(context.Context) error {
+			clock.Advance(10 * time.Millisecond)
+			err := script[attempt]
+			attempt++
+			return err
+		})
+	}
+}
+
+func TestCollector(t *testing.T) {
+	c := retrymetrics.NewCollector()
+	record(c, "db",
+		[]error{nil},
+		[]error{errTest, nil},
+		[]error{errTest, errTest, errTest},
+		[]error{retry.Stop(errTest)},
+	)
+	record(c, "cache", []error{nil})
+
+	snaps := c.Snapshot()
+	db := snaps["db"]
+	if db.Calls != 4 || db.Attempts != 7 || db.Retries != 3 || db.Successes != 2 {
+		t.Fatalf("unexpected counters %+v", db)
+	}
+	if db.GaveUp["exhausted"] != 1 || db.GaveUp["terminal"] != 1 || db.GaveUp["context"] != 0 {
+		t.Fatalf("unexpected give-ups %v", db.GaveUp)
+	}
+	if got := db.AttemptsPerCall; got.Count != 4 || got.Sum != 7 || got.Counts[0] != 2 || got.Counts[1] != 1 || got.Counts[2] != 1 {
+		t.Fatalf("unexpected attempts histogram %+v", got)
+	}
+	// Durations: 10ms, 120ms, 230ms and 10ms.
+	if got := db.CallDuration; got.Count != 4 || got.Sum < 0.369 || got.Sum > 0.371 {
+		t.Fatalf("unexpected duration histogram %+v", got)
+	}
+	if got := db.Sleep; got.Count != 3 || got.Counts[4] != 3 {
+		t.Fatalf("unexpected sleep histogram %+v", got)
+	}
+	if snaps["cache"].Calls != 1 {
+		t.Fatalf("unexpected cache metrics %+v", snaps["cache"])
+	}
+}
+
+func TestInstrumentSharesOperations(t *testing.T) {
+	c := retrymetrics.NewCollector()
+	record(c, "db", []error{nil})
+	record(c, "db", []error{nil})
+
+	if got := c.Snapshot()["db"].Calls; got != 2 {
+		t.Fatalf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestInstrumentNamedCalls(t *testing.T) {
+	c := retrymetrics.NewCollector()
+	instrument := c.Instrument("")
+	do := func(opts ...retry.Option) {
+		opts = append(opts, retry.WithMaxAttempts(1), instrument)
+		_ = retry.Do(context.Background(), func(context.Context) error { return errTest }, opts...)
+	}
+	do(retry.WithName("cache.get"))
+	do(retry.WithName("cache.get"), retry.WithLabels(map[string]string{"region": "us-east-1", "le": "x"}))
+	do(retry.WithName("cache.get"), retry.WithLabels(map[string]string{"region": "us-east-1"}))
+
+	snaps := c.Snapshot()
+	if len(snaps) != 2 {
+		t.Fatalf("expected 2 series, got %v", snaps)
+	}
+	if got := snaps["cache.get"]; got.Calls != 1 || got.Operation != "cache.get" || got.Labels != nil {
+		t.Fatalf("unexpected unlabeled series %+v", got)
+	}
+	got := snaps["cache.get [region=us-east-1]"]
+	if got.Calls != 2 || got.GaveUp["exhausted"] != 2 || got.Labels["region"] != "us-east-1" {
+		t.Fatalf("unexpected labeled series %+v", got)
+	}
+
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	for _, want := range []string{
+		`retry_calls_total{operation="cache.get",region="us-east-1"} 2` + "\n",
+		`retry_gave_up_total{operation="cache.get",region="us-east-1",reason="exhausted"} 2` + "\n",
+		`retry_attempts_per_call_bucket{operation="cache.get",region="us-east-1",le="1"} 2` + "\n",
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, rec.Body)
+		}
+	}
+}
+
+func TestInstrumentNameOverridesCallName(t *testing.T) {
+	c := retrymetrics.NewCollector()
+	_ = retry.Do(context.Background(), func(context.Context) error { return nil },
+		retry.WithName("cache.get"),
+		retry.WithLabels(map[string]string{"cache-tier": "l1"}),
+		c.Instrument("cache"),
+	)
+
+	got := c.Snapshot()["cache [cache_tier=l1]"]
+	if got.Calls != 1 || got.Operation != "cache" {
+		t.Fatalf("unexpected series %+v", c.Snapshot())
+	}
+}
+
+// publishRuns makes each TestPublish run's expvar name unique, since names
+// can be published only once per process.
+var publishRuns atomic.Int64
+
+func TestPublish(t *testing.T) {
+	c := retrymetrics.NewCollector()
+	record(c, "db", []error{errTest, nil})
+	name := fmt.Sprintf("%s_%d", t.Name(), publishRuns.Add(1))
+	c.Publish(name)
+
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatal("expected published variable")
+	}
+	var snaps map[string]retrymetrics.Snapshot
+	if err := json.Unmarshal([]byte(v.String()), &snaps); err != nil {
+		t.Fatal(err)
+	}
+	if snaps["db"].Retries != 1 || snaps["db"].GaveUp["exhausted"] != 0 {
+		t.Fatalf("unexpected published metrics %+v", snaps["db"])
+	}
+}
+
+func TestHandler(t *testing.T) {
+	c := retrymetrics.NewCollector()
+	record(c, `db "primary"`, []error{errTest, nil}, []error{errTest, errTest, errTest})
+
+	srv := httptest.NewServer(c.Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+
+	if ct := resp.Header.Get("Content-Type"); ct != retrymetrics.ContentType {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	for _, want := range []string{
+		"# TYPE retry_calls counter\n",
+		`retry_calls_total{operation="db \"primary\""} 2` + "\n",
+		`retry_attempts_total{operation="db \"primary\""} 5` + "\n",
+		`retry_retries_total{operation="db \"primary\""} 3` + "\n",
+		`retry_successes_total{operation="db \"primary\""} 1` + "\n",
+		`retry_gave_up_total{operation="db \"primary\"",reason="exhausted"} 1` + "\n",
+		`retry_gave_up_total{operation="db \"primary\"",reason="shutdown"} 0` + "\n",
+		"# TYPE retry_attempts_per_call histogram\n",
+		`retry_attempts_per_call_bucket{operation="db \"primary\"",le="1"} 0` + "\n",
+		`retry_attempts_per_call_bucket{operation="db \"primary\"",le="2"} 1` + "\n",
+		`retry_attempts_per_call_bucket{operation="db \"primary\"",le="3"} 2` + "\n",
+		`retry_attempts_per_call_bucket{operation="db \"primary\"",le="+Inf"} 2` + "\n",
+		`retry_attempts_per_call_sum{operation="db \"primary\""} 5` + "\n",
+		`retry_attempts_per_call_count{operation="db \"primary\""} 2` + "\n",
+		`retry_sleep_seconds_bucket{operation="db \"primary\"",le="0.1"} 3` + "\n",
+		`retry_call_duration_seconds_count{operation="db \"primary\""} 2` + "\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if !strings.HasSuffix(text, "# EOF\n") {
+		t.Errorf("expected # EOF terminator")
+	}
+}
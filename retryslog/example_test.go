@@ -0,0 +1,38 @@
+package retryslog_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retryslog"
+)
+
+// ExampleLog demonstrates logging retries of one operation.
+func ExampleLog() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == retryslog.KeyElapsed {
+				return slog.Attr{} // omitted for stable output
+			}
+			return a
+		},
+	}))
+
+	policy := retry.New(
+		retry.WithMaxAttempts(2),
+		retry.WithBackoff(retry.Constant(time.Millisecond)),
+		retryslog.Log(logger, retryslog.WithOperation("inventory.reserve")),
+	)
+
+	_ = policy.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	// Output:
+	// level=INFO msg=retrying operation=inventory.reserve attempt=1 delay=1ms error="connection refused"
+	// level=ERROR msg="giving up" operation=inventory.reserve attempt=2 error="connection refused" reason=exhausted
+}
@@ -0,0 +1,207 @@
+// Package retryslog logs retry activity through log/slog.
+//
+// Log returns a single retry.Option that logs attempts, retries, success
+// after retrying and giving up, with consistent attribute names:
+//
+//	operation  the operation name given to WithOperation or retry.WithName
+//	labels     a group of the labels given to retry.WithLabels
+//	attempt    the 1-based attempt number
+//	delay      the wait before the next attempt
+//	elapsed    time since the first attempt started
+//	error      the attempt's error
+//	reason     why the loop gave up (retry.StopReason)
+//
+// Use it on a policy or at a call site:
+//
+//	policy := retry.New(retryslog.Log(logger, retryslog.WithOperation("db")))
+//
+// Each event has its own level, and sampling keeps a retry storm from
+// flooding the logs.
+package retryslog
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+// Event identifies a kind of log record.
+type Event int
+
+const (
+	// EventAttempt is logged after every failed attempt. Default level Debug.
+	EventAttempt Event = iota
+	// EventRetry is logged before each retry sleep. Default level Info.
+	EventRetry
+	// EventSuccess is logged when a call succeeds after retrying.
+	// Default level Info.
+	EventSuccess
+	// EventGiveUp is logged when a call stops without succeeding.
+	// Default level Error.
+	EventGiveUp
+
+	numEvents = iota
+)
+
+// Attribute keys used in log records.
+const (
+	KeyOperation = "operation"
+	KeyLabels    = "labels"
+	KeyAttempt   = "attempt"
+	KeyDelay     = "delay"
+	KeyElapsed   = "elapsed"
+	KeyError     = "error"
+	KeyReason    = "reason"
+)
+
+// Option configures Log.
+type Option func(*logger)
+
+// WithOperation sets the operation attribute of every record, overriding the
+// call's retry.WithName name.
+func WithOperation(name string) Option {
+	return func(l *logger) {
+		l.operation = name
+	}
+}
+
+// WithLevel sets the level used for event.
+func WithLevel(event Event, level slog.Level) Option {
+	return func(l *logger) {
+		l.levels[event] = level
+	}
+}
+
+// WithSampling limits each event to the first records in every tick, then
+// to one in thereafter. With thereafter 0, records past first are dropped
+// until the next tick. Sampling is per event, so a flood of retries does not
+// hide give-ups.
+func WithSampling(first, thereafter int, tick time.Duration) Option {
+	return func(l *logger) {
+		for i := range l.samplers {
+			l.samplers[i] = &sampler{first: first, thereafter: thereafter, tick: tick}
+		}
+	}
+}
+
+type logger struct {
+	logger    *slog.Logger
+	operation string
+	levels    [numEvents]slog.Level
+	samplers  [numEvents]*sampler
+}
+
+// Log returns a retry.Option that logs retry activity through l.
+func Log(l *slog.Logger, opts ...Option) retry.Option {
+	lg := &logger{
+		logger: l,
+		levels: [numEvents]slog.Level{
+			EventAttempt: slog.LevelDebug,
+			EventRetry:   slog.LevelInfo,
+			EventSuccess: slog.LevelInfo,
+			EventGiveUp:  slog.LevelError,
+		},
+	}
+	for _, opt := range opts {
+		opt(lg)
+	}
+
+	return retry.Options(
+		retry.OnAttemptEnd(func(ctx context.Context, attempt int, err error, _ time.Duration) {
+			if err == nil {
+				return
+			}
+			lg.log(ctx, EventAttempt, "retry attempt failed",
+				slog.Int(KeyAttempt, attempt),
+				slog.Duration(KeyElapsed, elapsed(ctx)),
+				slog.Any(KeyError, err),
+			)
+		}),
+		retry.OnRetry(func(ctx context.Context, attempt int, err error, delay time.Duration) {
+			lg.log(ctx, EventRetry, "retrying",
+				slog.Int(KeyAttempt, attempt),
+				slog.Duration(KeyDelay, delay),
+				slog.Duration(KeyElapsed, elapsed(ctx)),
+				slog.Any(KeyError, err),
+			)
+		}),
+		retry.OnSuccess(func(ctx context.Context, attempts int) {
+			if attempts == 1 {
+				return
+			}
+			lg.log(ctx, EventSuccess, "succeeded after retrying",
+				slog.Int(KeyAttempt, attempts),
+				slog.Duration(KeyElapsed, elapsed(ctx)),
+			)
+		}),
+		retry.OnGiveUp(func(ctx context.Context, attempts int, err error, reason retry.StopReason) {
+			lg.log(ctx, EventGiveUp, "giving up",
+				slog.Int(KeyAttempt, attempts),
+				slog.Duration(KeyElapsed, elapsed(ctx)),
+				slog.Any(KeyError, err),
+				slog.String(KeyReason, reason.String()),
+			)
+		}),
+	)
+}
+
+func (l *logger) log(ctx context.Context, event Event, msg string, attrs ...slog.Attr) {
+	level := l.levels[event]
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	if s := l.samplers[event]; s != nil && !s.allow(time.Now()) {
+		return
+	}
+	op, _ := retry.OperationFromContext(ctx)
+	if l.operation != "" {
+		op.Name = l.operation
+	}
+	if len(op.Labels) > 0 {
+		group := make([]any, 0, len(op.Labels))
+		for _, k := range slices.Sorted(maps.Keys(op.Labels)) {
+			group = append(group, slog.String(k, op.Labels[k]))
+		}
+		attrs = append([]slog.Attr{slog.Group(KeyLabels, group...)}, attrs...)
+	}
+	if op.Name != "" {
+		attrs = append([]slog.Attr{slog.String(KeyOperation, op.Name)}, attrs...)
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func elapsed(ctx context.Context) time.Duration {
+	call, _ := retry.CallFromContext(ctx)
+	return call.Elapsed()
+}
+
+// sampler allows the first records in each tick, then every thereafter-th.
+type sampler struct {
+	first      int
+	thereafter int
+	tick       time.Duration
+
+	mu     sync.Mutex
+	window time.Time
+	count  int
+}
+
+func (s *sampler) allow(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.window) >= s.tick {
+		s.window = now
+		s.count = 0
+	}
+	s.count++
+	if s.count <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (s.count-s.first)%s.thereafter == 0
+}
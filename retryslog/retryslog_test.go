@@ -0,0 +1,219 @@
+package retryslog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retryslog"
+)
+
+var errTest = errors.New("test error")
+
+// fakeClock advances only when slept on.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(_ context.Context, d time.Duration) error {
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func newLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return slog.New(h), &buf
+}
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var recs []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+func TestLog(t *testing.T) {
+	t.Run("logs attempts, retries and giving up", func(t *testing.T) {
+		logger, buf := newLogger(slog.LevelDebug)
+
+		_ = retry.Do(context.Background(), func(context.Context) error { return errTest },
+			retry.WithMaxAttempts(2),
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(&fakeClock{now: time.Now()}),
+			retryslog.Log(logger, retryslog.WithOperation("db.query")),
+		)
+
+		recs := records(t, buf)
+		want := []struct {
+			level, msg string
+			elapsed    float64
+		}{
+			{"DEBUG", "retry attempt failed", 0},
+			{"INFO", "retrying", 0},
+			{"DEBUG", "retry attempt failed", float64(time.Second)},
+			{"ERROR", "giving up", float64(time.Second)},
+		}
+		if len(recs) != len(want) {
+			t.Fatalf("expected %d records, got %v", len(want), recs)
+		}
+		for i, w := range want {
+			r := recs[i]
+			if r["level"] != w.level || r["msg"] != w.msg || r["operation"] != "db.query" || r["error"] != "test error" || r["elapsed"] != w.elapsed {
+				t.Errorf("record %d: unexpected %v", i, r)
+			}
+		}
+		if recs[1]["delay"] != float64(time.Second) || recs[1]["attempt"] != float64(1) {
+			t.Errorf("unexpected retry record %v", recs[1])
+		}
+		if recs[3]["reason"] != "exhausted" || recs[3]["attempt"] != float64(2) {
+			t.Errorf("unexpected give-up record %v", recs[3])
+		}
+	})
+
+	t.Run("logs success only after retrying", func(t *testing.T) {
+		logger, buf := newLogger(slog.LevelInfo)
+		policy := retry.New(retry.WithClock(&fakeClock{}), retryslog.Log(logger))
+
+		_ = policy.Do(context.Background(), func(context.Context) error { return nil })
+		if buf.Len() != 0 {
+			t.Fatalf("expected no records for first-try success, got %s", buf)
+		}
+
+		calls := 0
+		_ = policy.Do(context.Background(), func(context.Context) error {
+			calls++
+			if calls == 1 {
+				return errTest
+			}
+			return nil
+		})
+		recs := records(t, buf)
+		if len(recs) != 2 || recs[1]["msg"] != "succeeded after retrying" || recs[1]["attempt"] != float64(2) {
+			t.Fatalf("unexpected records %v", recs)
+		}
+		if _, ok := recs[1]["operation"]; ok {
+			t.Fatalf("expected no operation attribute, got %v", recs[1])
+		}
+	})
+
+	t.Run("uses the call's name and labels", func(t *testing.T) {
+		logger, buf := newLogger(slog.LevelInfo)
+
+		_ = retry.Do(context.Background(), func(context.Context) error { return errTest },
+			retry.WithMaxAttempts(2),
+			retry.WithClock(&fakeClock{}),
+			retry.WithName("cache.get"),
+			retry.WithLabels(map[string]string{"region": "us-east-1"}),
+			retryslog.Log(logger),
+		)
+
+		recs := records(t, buf)
+		if len(recs) != 2 {
+			t.Fatalf("expected 2 records, got %v", recs)
+		}
+		for _, r := range recs {
+			labels, _ := r["labels"].(map[string]any)
+			if r["operation"] != "cache.get" || labels["region"] != "us-east-1" {
+				t.Fatalf("expected operation and labels, got %v", r)
+			}
+		}
+	})
+
+	t.Run("WithOperation overrides the call's name", func(t *testing.T) {
+		logger, buf := newLogger(slog.LevelInfo)
+
+		_ = retry.Do(context.Background(), func(context.Context) error { return errTest },
+			retry.WithMaxAttempts(1),
+			retry.WithName("cache.get"),
+			retryslog.Log(logger, retryslog.WithOperation("cache")),
+		)
+
+		recs := records(t, buf)
+		if len(recs) != 1 || recs[0]["operation"] != "cache" {
+			t.Fatalf("unexpected records %v", recs)
+		}
+	})
+
+	t.Run("levels per event", func(t *testing.T) {
+		logger, buf := newLogger(slog.LevelWarn)
+
+		_ = retry.Do(context.Background(), func(context.Context) error { return errTest },
+			retry.WithMaxAttempts(2),
+			retry.WithClock(&fakeClock{}),
+			retryslog.Log(logger,
+				retryslog.WithLevel(retryslog.EventRetry, slog.LevelWarn),
+				retryslog.WithLevel(retryslog.EventGiveUp, slog.LevelInfo),
+			),
+		)
+
+		recs := records(t, buf)
+		if len(recs) != 1 || recs[0]["level"] != "WARN" || recs[0]["msg"] != "retrying" {
+			t.Fatalf("unexpected records %v", recs)
+		}
+	})
+
+	t.Run("samples each event", func(t *testing.T) {
+		logger, buf := newLogger(slog.LevelInfo)
+
+		_ = retry.Do(context.Background(), func(context.Context) error { return errTest },
+			retry.WithMaxAttempts(11),
+			retry.WithClock(&fakeClock{}),
+			retryslog.Log(logger, retryslog.WithSampling(2, 3, time.Hour)),
+		)
+
+		var retries, giveUps []float64
+		for _, r := range records(t, buf) {
+			switch r["msg"] {
+			case "retrying":
+				retries = append(retries, r["attempt"].(float64))
+			case "giving up":
+				giveUps = append(giveUps, r["attempt"].(float64))
+			}
+		}
+		// 10 retries: the first 2, then every third of the rest.
+		if len(retries) != 4 || retries[0] != 1 || retries[1] != 2 || retries[2] != 5 || retries[3] != 8 {
+			t.Fatalf("unexpected sampled retries %v", retries)
+		}
+		if len(giveUps) != 1 {
+			t.Fatalf("expected the give-up to be sampled separately, got %v", giveUps)
+		}
+	})
+
+	t.Run("sampling without thereafter drops the rest", func(t *testing.T) {
+		logger, buf := newLogger(slog.LevelInfo)
+
+		_ = retry.Do(context.Background(), func(context.Context) error { return errTest },
+			retry.WithMaxAttempts(5),
+			retry.WithClock(&fakeClock{}),
+			retryslog.Log(logger, retryslog.WithSampling(1, 0, time.Hour)),
+		)
+
+		if n := strings.Count(buf.String(), `"msg":"retrying"`); n != 1 {
+			t.Fatalf("expected 1 retry record, got %d", n)
+		}
+	})
+}
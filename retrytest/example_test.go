@@ -0,0 +1,43 @@
+package retrytest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retrytest"
+)
+
+// UserService depends on retry.Retrier so tests can inject a Recorder.
+type UserService struct {
+	retrier retry.Retrier
+}
+
+func (s *UserService) Fetch(ctx context.Context) error {
+	return s.retrier.Do(ctx, func(ctx context.Context) error {
+		return nil
+	}, retry.If(isTransient))
+}
+
+// ExampleRecorder demonstrates asserting how a service uses retries.
+func ExampleRecorder() {
+	recorder := &retrytest.Recorder{
+		Outcomes: []error{errTransient, nil},
+	}
+	svc := &UserService{retrier: recorder}
+
+	err := svc.Fetch(context.Background())
+
+	call := recorder.Calls()[0]
+	fmt.Println("Error:", err)
+	fmt.Println("Attempts:", call.Attempts)
+	fmt.Println("Retries transient:", call.Retries(errTransient))
+	fmt.Println("Retries other:", call.Retries(errors.New("other")))
+
+	// Output:
+	// Error: <nil>
+	// Attempts: 2
+	// Retries transient: true
+	// Retries other: false
+}
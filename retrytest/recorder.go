@@ -0,0 +1,98 @@
+// Package retrytest provides test doubles for code that depends on retry.Retrier.
+package retrytest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+// Recorder is a retry.Retrier that runs a scripted sequence of attempts and
+// records every call it receives. The zero value is ready to use.
+//
+// Each Do call runs fn once per scripted outcome, through the real retry loop
+// with the call's options but without sleeping, so If conditions, Stop and
+// hooks behave as they would in production. The outcome, not fn's own result,
+// is what the loop sees for that attempt. With no outcomes, fn runs once and
+// its own result is used.
+type Recorder struct {
+	// Outcomes scripts the error reported by each attempt.
+	// A nil entry means the attempt succeeds.
+	Outcomes []error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ retry.Retrier = (*Recorder)(nil)
+
+// Call records a single Do call received by a Recorder.
+type Call struct {
+	// Opts are the options passed to Do.
+	Opts []retry.Option
+	// Attempts is how many times fn ran.
+	Attempts int
+	// Err is the error Do returned.
+	Err error
+}
+
+// Retries reports whether the call's options would retry err.
+// Use it to assert that a call site passed the expected If condition.
+func (c Call) Retries(err error) bool {
+	return retry.New(c.Opts...).Retryable(err)
+}
+
+// Do implements retry.Retrier.
+func (r *Recorder) Do(ctx context.Context, fn retry.Func, opts ...retry.Option) error {
+	outcomes := r.Outcomes
+	attempts := 0
+	attempt := func(ctx context.Context) error {
+		err := fn(ctx)
+		if attempts < len(outcomes) {
+			err = outcomes[attempts]
+		}
+		attempts++
+		return err
+	}
+
+	policy := retry.New(opts...).With(
+		retry.WithMaxAttempts(max(len(outcomes), 1)),
+		retry.WithMaxDuration(0),
+		retry.WithClock(instantClock{}),
+	)
+	err := policy.Do(ctx, attempt)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Opts: opts, Attempts: attempts, Err: err})
+	return err
+}
+
+// Calls returns the calls recorded so far, in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
+// Reset discards the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// instantClock is a retry.Clock that never sleeps.
+type instantClock struct{}
+
+func (instantClock) Now() time.Time {
+	return time.Now()
+}
+
+func (instantClock) Sleep(ctx context.Context, _ time.Duration) error {
+	return ctx.Err()
+}
@@ -0,0 +1,106 @@
+package retrytest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+	"github.com/bjaus/retry/retrytest"
+)
+
+var (
+	errTransient = errors.New("transient")
+	errFatal     = errors.New("fatal")
+)
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("runs fn once without outcomes", func(t *testing.T) {
+		var r retrytest.Recorder
+		runs := 0
+		err := r.Do(context.Background(), func(ctx context.Context) error {
+			runs++
+			return errTransient
+		})
+
+		if !errors.Is(err, errTransient) {
+			t.Fatalf("expected errTransient, got %v", err)
+		}
+		if runs != 1 {
+			t.Fatalf("expected 1 run, got %d", runs)
+		}
+	})
+
+	t.Run("runs fn once per scripted outcome", func(t *testing.T) {
+		r := retrytest.Recorder{Outcomes: []error{errTransient, errTransient, nil}}
+		runs := 0
+		err := r.Do(context.Background(), func(ctx context.Context) error {
+			runs++
+			return nil
+		}, retry.If(isTransient))
+
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if runs != 3 {
+			t.Fatalf("expected 3 runs, got %d", runs)
+		}
+		if calls := r.Calls(); len(calls) != 1 || calls[0].Attempts != 3 {
+			t.Fatalf("expected one call with 3 attempts, got %+v", calls)
+		}
+	})
+
+	t.Run("honors call options", func(t *testing.T) {
+		r := retrytest.Recorder{Outcomes: []error{errFatal, nil}}
+		var retries int
+		err := r.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		},
+			retry.If(isTransient),
+			retry.OnRetry(func(context.Context, int, error, time.Duration) { retries++ }),
+		)
+
+		if !errors.Is(err, errFatal) {
+			t.Fatalf("expected errFatal, got %v", err)
+		}
+		if calls := r.Calls(); calls[0].Attempts != 1 {
+			t.Fatalf("expected 1 attempt, got %d", calls[0].Attempts)
+		}
+		if retries != 0 {
+			t.Fatalf("expected no retries, got %d", retries)
+		}
+	})
+
+	t.Run("records options for assertions", func(t *testing.T) {
+		var r retrytest.Recorder
+		_ = r.Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		}, retry.If(isTransient))
+
+		calls := r.Calls()
+		if len(calls) != 1 {
+			t.Fatalf("expected 1 call, got %d", len(calls))
+		}
+		if !calls[0].Retries(errTransient) {
+			t.Fatal("expected call to retry errTransient")
+		}
+		if calls[0].Retries(errFatal) {
+			t.Fatal("expected call not to retry errFatal")
+		}
+	})
+
+	t.Run("Reset discards calls", func(t *testing.T) {
+		var r retrytest.Recorder
+		_ = r.Do(context.Background(), func(ctx context.Context) error { return nil })
+		r.Reset()
+
+		if calls := r.Calls(); len(calls) != 0 {
+			t.Fatalf("expected no calls, got %d", len(calls))
+		}
+	})
+}
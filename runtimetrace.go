@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+)
+
+// pprof label keys set by WithRuntimeTrace. Tasks and regions are named
+// like the spans of WithTracer: retry.Do, retry.attempt and retry.sleep.
+const (
+	LabelOperation = "retry.operation"
+	LabelAttempt   = "retry.attempt"
+)
+
+// WithRuntimeTrace makes calls visible to the Go execution tracer and
+// profiler. Each call runs in a runtime/trace task named "retry.Do", and each
+// attempt and sleep in a "retry.attempt" or "retry.sleep" region. During
+// attempts, the pprof labels retry.operation (set to operation, or the
+// WithName name if operation is empty) and retry.attempt are set, so CPU and
+// goroutine profiles attribute time to the operation and attempt that spent
+// it.
+//
+// Regions and tasks cost little when no trace is being recorded; labels are
+// set for every attempt.
+func WithRuntimeTrace(operation string) Option {
+	return func(c *config) {
+		c.runtimeTrace = true
+		c.traceOp = operation
+	}
+}
+
+// startTask starts the call's task when runtime tracing is enabled.
+func startTask(ctx context.Context, cfg *config) (context.Context, func()) {
+	if !cfg.runtimeTrace {
+		return ctx, func() {}
+	}
+	ctx, task := trace.NewTask(ctx, SpanDo)
+	if op := cfg.traceOperation(); op != "" {
+		trace.Log(ctx, LabelOperation, op)
+	}
+	return ctx, task.End
+}
+
+// runAttempt runs fn, in a region and with pprof labels when runtime tracing
+// is enabled.
+func runAttempt(ctx context.Context, cfg *config, fn Func, attempt int) error {
+	if !cfg.runtimeTrace {
+		return fn(ctx)
+	}
+	defer trace.StartRegion(ctx, SpanAttempt).End()
+	var err error
+	labels := pprof.Labels(LabelOperation, cfg.traceOperation(), LabelAttempt, strconv.Itoa(attempt))
+	pprof.Do(ctx, labels, func(ctx context.Context) {
+		err = fn(ctx)
+	})
+	return err
+}
+
+// sleepRegion starts a region for a sleep when runtime tracing is enabled.
+// The returned function ends it.
+func sleepRegion(ctx context.Context, cfg *config) func() {
+	if !cfg.runtimeTrace {
+		return func() {}
+	}
+	return trace.StartRegion(ctx, SpanSleep).End
+}
+
+// traceOperation returns the operation name for runtime tracing.
+func (c *config) traceOperation() string {
+	if c.traceOp != "" {
+		return c.traceOp
+	}
+	return c.name
+}
@@ -0,0 +1,71 @@
+package retry_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestRuntimeTrace(t *testing.T) {
+	t.Run("sets pprof labels during attempts", func(t *testing.T) {
+		type labels struct{ operation, attempt string }
+		var seen []labels
+		calls := 0
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			op, _ := pprof.Label(ctx, retry.LabelOperation)
+			attempt, _ := pprof.Label(ctx, retry.LabelAttempt)
+			seen = append(seen, labels{op, attempt})
+			if calls < 2 {
+				return errTest
+			}
+			return nil
+		}, retry.WithClock(newFakeClock()), retry.WithRuntimeTrace("db.query"))
+
+		if len(seen) != 2 || seen[0] != (labels{"db.query", "1"}) || seen[1] != (labels{"db.query", "2"}) {
+			t.Fatalf("unexpected labels %v", seen)
+		}
+	})
+
+	t.Run("no labels when disabled", func(t *testing.T) {
+		_ = retry.Do(context.Background(), func(ctx context.Context) error {
+			if _, ok := pprof.Label(ctx, retry.LabelAttempt); ok {
+				t.Fatal("expected no labels")
+			}
+			return nil
+		})
+	})
+
+	t.Run("records tasks and regions", func(t *testing.T) {
+		if trace.IsEnabled() {
+			t.Skip("execution trace already running")
+		}
+		var buf bytes.Buffer
+		if err := trace.Start(&buf); err != nil {
+			t.Fatal(err)
+		}
+		calls := 0
+		_ = retry.Do(context.Background(), func(context.Context) error {
+			calls++
+			if calls < 2 {
+				return errTest
+			}
+			return nil
+		},
+			retry.WithBackoff(retry.Constant(time.Millisecond)),
+			retry.WithRuntimeTrace("db.query"),
+		)
+		trace.Stop()
+
+		for _, want := range []string{retry.SpanDo, retry.SpanAttempt, retry.SpanSleep, "db.query"} {
+			if !bytes.Contains(buf.Bytes(), []byte(want)) {
+				t.Errorf("expected %q in the trace", want)
+			}
+		}
+	})
+}
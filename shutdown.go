@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrShuttingDown is returned, wrapping the last attempt's error, by Do calls
+// that stopped retrying because their policy was shut down.
+var ErrShuttingDown = errors.New("retry: policy is shutting down")
+
+// Shutdown stops retries on the policy, for example on SIGTERM. Sleeping and
+// paused retry loops wake immediately, attempts already running finish, and
+// every call that fails from then on returns an error wrapping both
+// ErrShuttingDown and its last error instead of retrying. New calls still
+// make their first attempt.
+//
+// Shutdown blocks until every Do call on the policy has returned or ctx is
+// done, in which case it returns ctx's error. It may be called more than
+// once. Shutdown applies to this Policy value only: policies derived with
+// With are not affected. On the zero Policy it does nothing.
+func (p *Policy) Shutdown(ctx context.Context) error {
+	if p.ctl == nil {
+		return nil
+	}
+	return p.ctl.life.shutdown(ctx)
+}
+
+// lifecycle counts a policy's running calls and signals shutdown.
+type lifecycle struct {
+	active  atomic.Int64
+	closing atomic.Bool
+	down    context.Context // canceled on shutdown
+	stop    context.CancelFunc
+
+	mu   sync.Mutex
+	idle chan struct{} // closed and replaced when calls drop to zero after shutdown
+}
+
+func (l *lifecycle) init() {
+	l.down, l.stop = context.WithCancel(context.Background())
+	l.idle = make(chan struct{})
+}
+
+func (l *lifecycle) begin() {
+	l.active.Add(1)
+}
+
+func (l *lifecycle) end() {
+	if l.active.Add(-1) == 0 && l.closing.Load() {
+		l.mu.Lock()
+		if l.active.Load() == 0 {
+			close(l.idle)
+			l.idle = make(chan struct{})
+		}
+		l.mu.Unlock()
+	}
+}
+
+func (l *lifecycle) shuttingDown() bool {
+	return l.closing.Load()
+}
+
+func (l *lifecycle) shutdown(ctx context.Context) error {
+	l.closing.Store(true)
+	l.stop()
+	for {
+		l.mu.Lock()
+		if l.active.Load() == 0 {
+			l.mu.Unlock()
+			return nil
+		}
+		idle := l.idle
+		l.mu.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// waitContext returns a context for sleeping between attempts that is also
+// canceled when the policy shuts down. Call both cancel and stop when done.
+func (l *lifecycle) waitContext(ctx context.Context) (wctx context.Context, cancel context.CancelFunc, stop func() bool) {
+	wctx, cancel = context.WithCancel(ctx)
+	return wctx, cancel, context.AfterFunc(l.down, cancel)
+}
+
+// shutdownError wraps err for a call stopped by Shutdown.
+func shutdownError(err error) error {
+	return fmt.Errorf("%w: %w", ErrShuttingDown, err)
+}
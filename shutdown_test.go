@@ -0,0 +1,267 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("wakes sleeping retries", func(t *testing.T) {
+		p := retry.New(retry.WithBackoff(retry.Constant(time.Hour)))
+
+		sleeping := make(chan struct{})
+		done := make(chan error)
+		var reason retry.StopReason
+		go func() {
+			done <- p.Do(context.Background(), func(context.Context) error { return errTest },
+				retry.OnRetry(func(context.Context, int, error, time.Duration) { close(sleeping) }),
+				retry.OnGiveUp(func(_ context.Context, _ int, _ error, r retry.StopReason) { reason = r }),
+			)
+		}()
+		<-sleeping
+
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := <-done
+		if !errors.Is(err, retry.ErrShuttingDown) || !errors.Is(err, errTest) {
+			t.Fatalf("expected ErrShuttingDown wrapping errTest, got %v", err)
+		}
+		if err.Error() != "retry: policy is shutting down: test error" {
+			t.Fatalf("unexpected message %q", err)
+		}
+		if reason != retry.StopShutdown {
+			t.Fatalf("expected StopShutdown, got %v", reason)
+		}
+	})
+
+	t.Run("wakes paused retries", func(t *testing.T) {
+		clock := newBlockingClock()
+		p := retry.New(retry.WithBackoff(retry.Constant(0)), retry.WithClock(clock))
+		p.SetOverrides(retry.Overrides{Paused: true})
+
+		done := make(chan error)
+		go func() {
+			done <- p.Do(context.Background(), func(context.Context) error { return errTest })
+		}()
+		<-clock.sleeping
+
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := <-done; !errors.Is(err, retry.ErrShuttingDown) {
+			t.Fatalf("expected ErrShuttingDown, got %v", err)
+		}
+	})
+
+	t.Run("lets running attempts finish", func(t *testing.T) {
+		p := retry.New(retry.WithBackoff(retry.Constant(0)))
+
+		running := make(chan struct{})
+		release := make(chan error)
+		done := make(chan error)
+		calls := 0
+		go func() {
+			done <- p.Do(context.Background(), func(context.Context) error {
+				calls++
+				close(running)
+				return <-release
+			})
+		}()
+		<-running
+
+		shutdown := make(chan error)
+		go func() { shutdown <- p.Shutdown(context.Background()) }()
+		select {
+		case <-shutdown:
+			t.Fatal("expected Shutdown to wait for the running call")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		release <- errTest
+		if err := <-done; !errors.Is(err, retry.ErrShuttingDown) || calls != 1 {
+			t.Fatalf("expected ErrShuttingDown after 1 call, got %v after %d", err, calls)
+		}
+		if err := <-shutdown; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("successful attempts still succeed", func(t *testing.T) {
+		p := retry.New()
+
+		running := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			done <- p.Do(context.Background(), func(context.Context) error {
+				close(running)
+				<-release
+				return nil
+			})
+		}()
+		<-running
+
+		shutdown := make(chan error)
+		go func() { shutdown <- p.Shutdown(context.Background()) }()
+		close(release)
+		if err := <-done; err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if err := <-shutdown; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("gives up waiting when ctx is done", func(t *testing.T) {
+		p := retry.New()
+
+		running := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			done <- p.Do(context.Background(), func(context.Context) error {
+				close(running)
+				<-release
+				return nil
+			})
+		}()
+		<-running
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := p.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected DeadlineExceeded, got %v", err)
+		}
+
+		close(release)
+		<-done
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("new calls make one attempt", func(t *testing.T) {
+		p := retry.New(retry.WithClock(newFakeClock()))
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		calls := 0
+		err := p.Do(context.Background(), func(context.Context) error {
+			calls++
+			return errTest
+		})
+		if !errors.Is(err, retry.ErrShuttingDown) || calls != 1 {
+			t.Fatalf("expected ErrShuttingDown after 1 call, got %v after %d", err, calls)
+		}
+		if err := p.Do(context.Background(), func(context.Context) error { return nil }); err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+	})
+
+	t.Run("context cancellation is still reported as such", func(t *testing.T) {
+		p := retry.New(retry.WithBackoff(retry.Constant(time.Hour)))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var reason retry.StopReason
+		err := p.Do(ctx, func(context.Context) error { return errTest },
+			retry.OnRetry(func(context.Context, int, error, time.Duration) { cancel() }),
+			retry.OnGiveUp(func(_ context.Context, _ int, _ error, r retry.StopReason) { reason = r }),
+		)
+		if errors.Is(err, retry.ErrShuttingDown) || reason != retry.StopContext {
+			t.Fatalf("expected StopContext, got %v (%v)", err, reason)
+		}
+	})
+
+	t.Run("derived policies are not affected", func(t *testing.T) {
+		p := retry.New(retry.WithClock(newFakeClock()))
+		child := p.With()
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		calls := 0
+		err := child.Do(context.Background(), func(context.Context) error {
+			calls++
+			return errTest
+		})
+		if errors.Is(err, retry.ErrShuttingDown) || calls != 3 {
+			t.Fatalf("expected 3 calls, got %v after %d", err, calls)
+		}
+	})
+
+	t.Run("wakes and waits by default", func(t *testing.T) {
+		p := retry.New(retry.WithBackoff(retry.Constant(200 * time.Millisecond)))
+
+		sleeping := make(chan struct{})
+		done := make(chan error, 1)
+		calls := 0
+		go func() {
+			done <- p.Do(context.Background(), func(context.Context) error {
+				calls++
+				return errTest
+			}, retry.OnRetry(func(context.Context, int, error, time.Duration) { close(sleeping) }))
+		}()
+		<-sleeping
+
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		select {
+		case err := <-done:
+			if !errors.Is(err, retry.ErrShuttingDown) || calls != 1 {
+				t.Fatalf("expected ErrShuttingDown after 1 call, got %v after %d", err, calls)
+			}
+		default:
+			t.Fatal("expected the call to return before Shutdown")
+		}
+	})
+
+	t.Run("starts no attempt after Shutdown", func(t *testing.T) {
+		var p *retry.Policy
+		shutdown := make(chan error)
+		clock := uninterruptedClock{onSleep: func() {
+			go func() { shutdown <- p.Shutdown(context.Background()) }()
+		}}
+		p = retry.New(retry.WithBackoff(retry.Constant(time.Second)), retry.WithClock(clock))
+
+		calls := 0
+		err := p.Do(context.Background(), func(context.Context) error {
+			calls++
+			return errTest
+		})
+		if !errors.Is(err, retry.ErrShuttingDown) || calls != 1 {
+			t.Fatalf("expected ErrShuttingDown after 1 call, got %v after %d", err, calls)
+		}
+		if err := <-shutdown; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("zero policy", func(t *testing.T) {
+		var p retry.Policy
+		p.SetOverrides(retry.Overrides{MaxAttempts: 1})
+		if err := p.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+// uninterruptedClock sleeps until ctx is done but reports the sleep as
+// complete, like a sleep that ends just as Shutdown begins.
+type uninterruptedClock struct {
+	onSleep func()
+}
+
+func (uninterruptedClock) Now() time.Time { return time.Now() }
+
+func (c uninterruptedClock) Sleep(ctx context.Context, _ time.Duration) error {
+	c.onSleep()
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer starts spans for the retry loop. It is deliberately small so any
+// tracing library can be adapted to it without this package depending on one.
+//
+// With WithTracer, each Do call produces a "retry.Do" span covering the whole
+// call, with child spans "retry.attempt" for every attempt and "retry.sleep"
+// for every wait between attempts. fn runs under its attempt's span.
+type Tracer interface {
+	// Start starts a span named name as a child of any span in ctx and
+	// returns a context carrying the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttributes records attributes on the span.
+	SetAttributes(attrs ...Attribute)
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+	// End finishes the span.
+	End()
+}
+
+// Attribute is a key/value pair recorded on a span. Value is an int, bool,
+// string or time.Duration.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span names and attribute keys used by the retry loop.
+const (
+	SpanDo      = "retry.Do"
+	SpanAttempt = "retry.attempt"
+	SpanSleep   = "retry.sleep"
+
+	AttrOperation   = "retry.operation"    // string, on retry.Do for WithName
+	AttrLabelPrefix = "retry.label."       // string, on retry.Do per WithLabels label
+	AttrMaxAttempts = "retry.max_attempts" // int, on retry.Do
+	AttrAttempts    = "retry.attempts"     // int, on retry.Do
+	AttrStopReason  = "retry.stop_reason"  // string, on retry.Do when it fails
+	AttrAttempt     = "retry.attempt"      // int, on retry.attempt and retry.sleep
+	AttrDelay       = "retry.delay"        // time.Duration, on retry.sleep
+)
+
+// WithTracer traces every call with t. Passing nil disables tracing.
+func WithTracer(t Tracer) Option {
+	return func(c *config) {
+		c.tracer = t
+	}
+}
+
+// callSpan is the retry.Do span of a traced call. A nil callSpan is a no-op,
+// so the retry loop can use it unconditionally.
+type callSpan struct {
+	tracer Tracer
+	span   Span
+}
+
+func startCall(ctx context.Context, t Tracer, maxAttempts int, op Operation) (context.Context, *callSpan) {
+	if t == nil {
+		return ctx, nil
+	}
+	ctx, span := t.Start(ctx, SpanDo)
+	span.SetAttributes(Attribute{AttrMaxAttempts, maxAttempts})
+	if op.Name != "" {
+		span.SetAttributes(Attribute{AttrOperation, op.Name})
+	}
+	for k, v := range op.Labels {
+		span.SetAttributes(Attribute{AttrLabelPrefix + k, v})
+	}
+	return ctx, &callSpan{tracer: t, span: span}
+}
+
+func (c *callSpan) end(attempts int, err error, reason StopReason) {
+	if c == nil {
+		return
+	}
+	c.span.SetAttributes(Attribute{AttrAttempts, attempts})
+	if err != nil {
+		c.span.SetAttributes(Attribute{AttrStopReason, reason.String()})
+		c.span.RecordError(err)
+	}
+	c.span.End()
+}
+
+func (c *callSpan) attempt(ctx context.Context, attempt int) (context.Context, Span) {
+	if c == nil {
+		return ctx, nil
+	}
+	ctx, span := c.tracer.Start(ctx, SpanAttempt)
+	span.SetAttributes(Attribute{AttrAttempt, attempt})
+	return ctx, span
+}
+
+func (c *callSpan) sleep(ctx context.Context, attempt int, delay time.Duration) Span {
+	if c == nil {
+		return nil
+	}
+	_, span := c.tracer.Start(ctx, SpanSleep)
+	span.SetAttributes(Attribute{AttrAttempt, attempt}, Attribute{AttrDelay, delay})
+	return span
+}
+
+// endSpan records err, if any, on span and ends it. A nil span is ignored.
+func endSpan(span Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
@@ -0,0 +1,149 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+// recordingTracer records spans as "parent>name" with their attributes and errors.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]any
+	err    error
+	ended  bool
+}
+
+type spanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, retry.Span) {
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	s := &recordedSpan{name: name, parent: parent, attrs: map[string]any{}}
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...retry.Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+
+func (s *recordedSpan) End() { s.ended = true }
+
+func (s *recordedSpan) String() string {
+	name := s.name
+	if s.parent != nil {
+		name = s.parent.name + ">" + name
+	}
+	return name
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("spans for the call, attempts and sleeps", func(t *testing.T) {
+		tracer := &recordingTracer{}
+		calls := 0
+		var fnSpan any
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			fnSpan = ctx.Value(spanKey{})
+			if calls < 2 {
+				return errTest
+			}
+			return nil
+		},
+			retry.WithBackoff(retry.Constant(time.Second)),
+			retry.WithClock(newFakeClock()),
+			retry.WithTracer(tracer),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var names []string
+		for _, s := range tracer.spans {
+			names = append(names, s.String())
+			if !s.ended {
+				t.Errorf("span %s not ended", s)
+			}
+		}
+		if got := strings.Join(names, " "); got != "retry.Do retry.Do>retry.attempt retry.Do>retry.sleep retry.Do>retry.attempt" {
+			t.Fatalf("unexpected spans %s", got)
+		}
+
+		do, first, sleep, second := tracer.spans[0], tracer.spans[1], tracer.spans[2], tracer.spans[3]
+		if do.attrs[retry.AttrMaxAttempts] != 3 || do.attrs[retry.AttrAttempts] != 2 || do.err != nil {
+			t.Errorf("unexpected call span %+v", do)
+		}
+		if _, ok := do.attrs[retry.AttrStopReason]; ok {
+			t.Errorf("unexpected stop reason on success %+v", do.attrs)
+		}
+		if first.attrs[retry.AttrAttempt] != 1 || !errors.Is(first.err, errTest) {
+			t.Errorf("unexpected first attempt span %+v", first)
+		}
+		if sleep.attrs[retry.AttrDelay] != time.Second || sleep.attrs[retry.AttrAttempt] != 1 || sleep.err != nil {
+			t.Errorf("unexpected sleep span %+v", sleep)
+		}
+		if second.attrs[retry.AttrAttempt] != 2 || second.err != nil {
+			t.Errorf("unexpected second attempt span %+v", second)
+		}
+		if fnSpan != second {
+			t.Errorf("expected fn to run under its attempt span")
+		}
+	})
+
+	t.Run("records the final error and stop reason", func(t *testing.T) {
+		tracer := &recordingTracer{}
+		_ = retry.Do(context.Background(), func(context.Context) error { return errTest },
+			retry.WithMaxAttempts(2),
+			retry.WithClock(newFakeClock()),
+			retry.WithTracer(tracer),
+		)
+
+		do := tracer.spans[0]
+		if !errors.Is(do.err, errTest) || do.attrs[retry.AttrStopReason] != "exhausted" || do.attrs[retry.AttrAttempts] != 2 {
+			t.Fatalf("unexpected call span %+v", do)
+		}
+	})
+
+	t.Run("records interrupted sleeps", func(t *testing.T) {
+		tracer := &recordingTracer{}
+		ctx, cancel := context.WithCancel(context.Background())
+		_ = retry.Do(ctx, func(context.Context) error {
+			cancel()
+			return errTest
+		}, retry.WithClock(newFakeClock()), retry.WithTracer(tracer))
+
+		sleep := tracer.spans[2]
+		if sleep.name != retry.SpanSleep || !errors.Is(sleep.err, context.Canceled) || !sleep.ended {
+			t.Fatalf("unexpected sleep span %+v", sleep)
+		}
+		if got := tracer.spans[0].attrs[retry.AttrStopReason]; got != "context" {
+			t.Fatalf("expected context stop reason, got %v", got)
+		}
+	})
+
+	t.Run("nil disables tracing", func(t *testing.T) {
+		tracer := &recordingTracer{}
+		p := retry.New(retry.WithTracer(tracer), retry.WithClock(newFakeClock()))
+		_ = p.Do(context.Background(), func(context.Context) error { return nil }, retry.WithTracer(nil))
+		if len(tracer.spans) != 0 {
+			t.Fatalf("expected no spans, got %d", len(tracer.spans))
+		}
+	})
+}
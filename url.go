@@ -0,0 +1,190 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromURLValues builds a policy from URL query parameters, so retry settings
+// can ride along in connection strings:
+//
+//	postgres://db/app?sslmode=disable&retry_max=5&retry_timeout=30s&retry_backoff=exp:100ms,cap:10s,jitter:0.2
+//
+// With prefix "retry" it reads:
+//
+//	retry_max      maximum attempts
+//	retry_timeout  total time budget (MaxDuration)
+//	retry_backoff  comma-separated steps: a base strategy followed by wrappers
+//
+// A backoff step is name:value. The first step is exp (or exponential), const
+// (or constant) or lin (or linear) with a duration; each following step wraps
+// the result with cap or min and a duration, or jitter and a factor. The
+// example above is jitter(0.2, cap(10s, exponential(100ms))).
+//
+// With an empty prefix the parameters are max, timeout and backoff. Absent
+// parameters keep DefaultPolicy's settings; other parameters are ignored.
+func FromURLValues(values url.Values, prefix string) (*Policy, error) {
+	var opts []Option
+	var errs []error
+	param := func(name string, parse func(string) (Option, error)) {
+		key := urlKey(prefix, name)
+		vs, ok := values[key]
+		if !ok {
+			return
+		}
+		if len(vs) != 1 {
+			errs = append(errs, fmt.Errorf("retry: %s: expected one value, got %d", key, len(vs)))
+			return
+		}
+		opt, err := parse(vs[0])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("retry: %s=%q: %w", key, vs[0], err))
+			return
+		}
+		opts = append(opts, opt)
+	}
+
+	param("max", func(v string) (Option, error) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.New("not an integer")
+		}
+		return WithMaxAttempts(n), nil
+	})
+	param("timeout", func(v string) (Option, error) {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, errors.New("not a duration such as 30s")
+		}
+		return WithMaxDuration(d), nil
+	})
+	param("backoff", func(v string) (Option, error) {
+		b, err := parseBackoffSteps(v)
+		if err != nil {
+			return nil, err
+		}
+		return WithBackoff(b), nil
+	})
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	p := DefaultPolicy().With(opts...)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ToURLValues encodes the policy's budget and backoff as query parameters in
+// the format read by FromURLValues. It fails if the backoff is not one of the
+// built-in ones.
+func ToURLValues(p *Policy, prefix string) (url.Values, error) {
+	steps, err := backoffSteps(p.cfg.backoff)
+	if err != nil {
+		return nil, err
+	}
+	d := p.Describe()
+	values := url.Values{}
+	values.Set(urlKey(prefix, "max"), strconv.Itoa(d.MaxAttempts))
+	if d.MaxDuration > 0 {
+		values.Set(urlKey(prefix, "timeout"), d.MaxDuration.String())
+	}
+	values.Set(urlKey(prefix, "backoff"), steps)
+	return values, nil
+}
+
+func urlKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// parseBackoffSteps parses the step list of a backoff query parameter.
+func parseBackoffSteps(s string) (Backoff, error) {
+	var b Backoff
+	for i, step := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(step, ":")
+		if !ok {
+			return nil, fmt.Errorf("step %d %q: expected name:value", i+1, step)
+		}
+
+		if i == 0 {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("step %d %q: invalid duration", i+1, step)
+			}
+			switch name {
+			case "exp", "exponential":
+				b = Exponential(d)
+			case "const", "constant":
+				b = Constant(d)
+			case "lin", "linear":
+				b = Linear(d)
+			default:
+				return nil, fmt.Errorf("step %d %q: expected exp, const or lin", i+1, step)
+			}
+			continue
+		}
+
+		switch name {
+		case "cap", "min":
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("step %d %q: invalid duration", i+1, step)
+			}
+			if name == "cap" {
+				b = WithCap(d, b)
+			} else {
+				b = WithMin(d, b)
+			}
+		case "jitter":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("step %d %q: invalid factor", i+1, step)
+			}
+			b = WithJitter(f, b)
+		default:
+			return nil, fmt.Errorf("step %d %q: expected cap, min or jitter", i+1, step)
+		}
+	}
+	return b, nil
+}
+
+// backoffSteps encodes b as a step list, innermost strategy first.
+func backoffSteps(b Backoff) (string, error) {
+	var steps []string
+	for {
+		switch w := b.(type) {
+		case CapBackoff:
+			steps = append(steps, "cap:"+w.max.String())
+			b = w.b
+			continue
+		case MinBackoff:
+			steps = append(steps, "min:"+w.min.String())
+			b = w.b
+			continue
+		case JitterBackoff:
+			steps = append(steps, "jitter:"+strconv.FormatFloat(w.factor, 'g', -1, 64))
+			b = w.b
+			continue
+		case ExponentialBackoff:
+			steps = append(steps, "exp:"+w.base.String())
+		case ConstantBackoff:
+			steps = append(steps, "const:"+w.d.String())
+		case LinearBackoff:
+			steps = append(steps, "lin:"+w.base.String())
+		default:
+			return "", fmt.Errorf("retry: cannot encode %s backoff", backoffString(b))
+		}
+		break
+	}
+	slices.Reverse(steps)
+	return strings.Join(steps, ","), nil
+}
@@ -0,0 +1,132 @@
+package retry_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestFromURLValues(t *testing.T) {
+	t.Run("reads prefixed parameters", func(t *testing.T) {
+		values, _ := url.ParseQuery("sslmode=disable&retry_max=5&retry_timeout=30s&retry_backoff=exp:100ms,cap:10s,jitter:0.2")
+
+		p, err := retry.FromURLValues(values, "retry")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d := p.Describe()
+		if d.MaxAttempts != 5 || d.MaxDuration != 30*time.Second || d.Backoff != "jitter(0.2, cap(10s, exponential(100ms)))" {
+			t.Fatalf("unexpected policy %+v", d)
+		}
+	})
+
+	t.Run("empty prefix", func(t *testing.T) {
+		values := url.Values{"max": {"2"}, "backoff": {"constant:1s,min:2s"}}
+
+		p, err := retry.FromURLValues(values, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := p.String(); got != "attempts=2 backoff=min(2s, constant(1s))" {
+			t.Fatalf("unexpected policy %s", got)
+		}
+	})
+
+	t.Run("absent parameters keep the default policy", func(t *testing.T) {
+		p, err := retry.FromURLValues(url.Values{"sslmode": {"disable"}}, "retry")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.String() != retry.DefaultPolicy().String() {
+			t.Fatalf("expected %s, got %s", retry.DefaultPolicy(), p)
+		}
+	})
+
+	t.Run("reports every invalid parameter", func(t *testing.T) {
+		values := url.Values{
+			"retry_max":     {"many"},
+			"retry_timeout": {"1s", "2s"},
+			"retry_backoff": {"exp:100ms,fib:1s"},
+		}
+
+		_, err := retry.FromURLValues(values, "retry")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		for _, want := range []string{
+			`retry: retry_max="many": not an integer`,
+			"retry: retry_timeout: expected one value, got 2",
+			`retry: retry_backoff="exp:100ms,fib:1s": step 2 "fib:1s": expected cap, min or jitter`,
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q in:\n%v", want, err)
+			}
+		}
+	})
+
+	t.Run("rejects malformed backoff steps", func(t *testing.T) {
+		for _, backoff := range []string{"", "exp", "cap:1s", "exp:soon", "exp:1s,jitter:lots", "exp:1s,cap:"} {
+			_, err := retry.FromURLValues(url.Values{"backoff": {backoff}}, "")
+			if err == nil {
+				t.Errorf("expected error for %q", backoff)
+			}
+		}
+	})
+
+	t.Run("validates the result", func(t *testing.T) {
+		_, err := retry.FromURLValues(url.Values{"backoff": {"exp:1s,jitter:5"}}, "")
+		if err == nil || !strings.Contains(err.Error(), "jitter factor 5 is outside [0, 1]") {
+			t.Fatalf("expected jitter error, got %v", err)
+		}
+	})
+}
+
+func TestToURLValues(t *testing.T) {
+	t.Run("round trips", func(t *testing.T) {
+		p := retry.New(
+			retry.WithMaxAttempts(4),
+			retry.WithMaxDuration(time.Minute),
+			retry.WithBackoff(retry.WithJitter(0.5, retry.WithMin(time.Second, retry.Linear(200*time.Millisecond)))),
+		)
+
+		values, err := retry.ToURLValues(p, "db_retry")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := values.Get("db_retry_backoff"); got != "lin:200ms,min:1s,jitter:0.5" {
+			t.Fatalf("unexpected backoff %q", got)
+		}
+
+		back, err := retry.FromURLValues(values, "db_retry")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if back.String() != p.String() {
+			t.Fatalf("expected %s, got %s", p, back)
+		}
+	})
+
+	t.Run("omits an unlimited timeout", func(t *testing.T) {
+		values, err := retry.ToURLValues(retry.New(retry.WithBackoff(retry.Constant(time.Second))), "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if values.Has("timeout") {
+			t.Fatalf("unexpected timeout %q", values.Get("timeout"))
+		}
+		if got := values.Encode(); got != "backoff=const%3A1s&max=3" {
+			t.Fatalf("unexpected encoding %q", got)
+		}
+	})
+
+	t.Run("rejects custom backoffs", func(t *testing.T) {
+		p := retry.New(retry.WithBackoff(retry.BackoffFunc(func(int) time.Duration { return 0 })))
+
+		if _, err := retry.ToURLValues(p, "retry"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ConfigError describes one invalid policy setting.
+type ConfigError struct {
+	// Field names the setting, such as "MaxAttempts" or "Backoff".
+	Field string
+	// Reason explains what is wrong with it.
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return "retry: invalid " + e.Field + ": " + e.Reason
+}
+
+// NewChecked creates a Policy like New, but returns an error describing every
+// misconfiguration instead of accepting it silently.
+func NewChecked(opts ...Option) (*Policy, error) {
+	p := New(opts...)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate reports every misconfiguration in the policy. The returned error
+// joins one *ConfigError per problem; use errors.As to inspect them.
+//
+// Validate checks for negative attempt counts and durations, a nil Backoff
+// or Clock, invalid settings in the built-in backoff strategies (negative
+// delays, jitter factors outside [0, 1], a cap below a min), nil middleware
+// or remediations, and a MaxDuration shorter than the minimum backoff delay.
+func (p *Policy) Validate() error {
+	return p.cfg.validate()
+}
+
+func (c *config) validate() error {
+	var errs []error
+	add := func(field, format string, args ...any) {
+		errs = append(errs, &ConfigError{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if c.maxAttempts < 0 {
+		add("MaxAttempts", "%d is negative", c.maxAttempts)
+	}
+	if c.maxDuration < 0 {
+		add("MaxDuration", "%v is negative", c.maxDuration)
+	}
+	if c.clock == nil {
+		add("Clock", "clock is nil")
+	}
+	for _, problem := range validateBackoff(c.backoff, nil) {
+		add("Backoff", "%s", problem)
+	}
+	for i, mw := range c.middleware {
+		if mw == nil {
+			add("Middleware", "middleware %d is nil", i)
+		}
+	}
+	for i, r := range c.remedies {
+		if r.fn == nil {
+			add("OnError", "remediation %d is nil", i)
+		}
+	}
+
+	// A budget is impossible when even the shortest backoff delay does not
+	// fit in it, so no retry can ever wait as configured.
+	if c.maxDuration > 0 && c.maxAttempts != 1 {
+		if floor, ok := floorOf(c.backoff); ok && floor > c.maxDuration {
+			add("MaxDuration", "%v is shorter than the minimum backoff delay %v", c.maxDuration, floor)
+		}
+	}
+
+	return errors.Join(errs...)
+}
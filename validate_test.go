@@ -0,0 +1,169 @@
+package retry_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjaus/retry"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		opts   []retry.Option
+		field  string
+		reason string
+	}{
+		{
+			name:   "negative max attempts",
+			opts:   []retry.Option{retry.WithMaxAttempts(-1)},
+			field:  "MaxAttempts",
+			reason: "-1 is negative",
+		},
+		{
+			name:   "negative max duration",
+			opts:   []retry.Option{retry.WithMaxDuration(-time.Second)},
+			field:  "MaxDuration",
+			reason: "-1s is negative",
+		},
+		{
+			name:   "nil backoff",
+			opts:   []retry.Option{retry.WithBackoff(nil)},
+			field:  "Backoff",
+			reason: "backoff is nil",
+		},
+		{
+			name:   "nil wrapped backoff",
+			opts:   []retry.Option{retry.WithBackoff(retry.WithCap(time.Second, nil))},
+			field:  "Backoff",
+			reason: "backoff is nil",
+		},
+		{
+			name:   "nil clock",
+			opts:   []retry.Option{retry.WithClock(nil)},
+			field:  "Clock",
+			reason: "clock is nil",
+		},
+		{
+			name:   "negative constant",
+			opts:   []retry.Option{retry.WithBackoff(retry.Constant(-time.Second))},
+			field:  "Backoff",
+			reason: "constant delay -1s is negative",
+		},
+		{
+			name:   "jitter factor too large",
+			opts:   []retry.Option{retry.WithBackoff(retry.WithJitter(5, retry.Exponential(time.Second)))},
+			field:  "Backoff",
+			reason: "jitter factor 5 is outside [0, 1]",
+		},
+		{
+			name: "cap below min",
+			opts: []retry.Option{retry.WithBackoff(
+				retry.WithCap(time.Second, retry.WithMin(2*time.Second, retry.Exponential(time.Millisecond))),
+			)},
+			field:  "Backoff",
+			reason: "cap 1s is below min 2s",
+		},
+		{
+			name: "max duration shorter than minimum delay",
+			opts: []retry.Option{
+				retry.WithMaxDuration(time.Second),
+				retry.WithBackoff(retry.Constant(2 * time.Second)),
+			},
+			field:  "MaxDuration",
+			reason: "1s is shorter than the minimum backoff delay 2s",
+		},
+		{
+			name:   "nil middleware",
+			opts:   []retry.Option{retry.WithMiddleware(nil)},
+			field:  "Middleware",
+			reason: "middleware 0 is nil",
+		},
+		{
+			name:   "nil remediation",
+			opts:   []retry.Option{retry.OnError(nil, nil)},
+			field:  "OnError",
+			reason: "remediation 0 is nil",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := retry.New(tc.opts...).Validate()
+
+			var cfgErr *retry.ConfigError
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("expected *ConfigError, got %v", err)
+			}
+			if cfgErr.Field != tc.field || cfgErr.Reason != tc.reason {
+				t.Fatalf("expected %s: %s, got %s: %s", tc.field, tc.reason, cfgErr.Field, cfgErr.Reason)
+			}
+		})
+	}
+
+	t.Run("valid policies", func(t *testing.T) {
+		policies := map[string]*retry.Policy{
+			"New":     retry.New(),
+			"Default": retry.Default(),
+			"Never":   retry.Never(),
+			"budget": retry.New(
+				retry.WithMaxDuration(time.Second),
+				retry.WithBackoff(retry.WithJitter(0.5, retry.Constant(time.Second))),
+			),
+			"custom": retry.New(retry.WithBackoff(retry.BackoffFunc(func(int) time.Duration {
+				return -time.Second
+			}))),
+		}
+		for name, p := range policies {
+			if err := p.Validate(); err != nil {
+				t.Errorf("%s: expected nil error, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		err := retry.New(
+			retry.WithMaxAttempts(-1),
+			retry.WithMaxDuration(-time.Second),
+			retry.WithClock(nil),
+			retry.WithBackoff(retry.WithJitter(2, retry.Linear(-time.Second))),
+		).Validate()
+
+		msg := err.Error()
+		for _, want := range []string{
+			"retry: invalid MaxAttempts: -1 is negative",
+			"retry: invalid MaxDuration: -1s is negative",
+			"retry: invalid Clock: clock is nil",
+			"retry: invalid Backoff: jitter factor 2 is outside [0, 1]",
+			"retry: invalid Backoff: linear base -1s is negative",
+		} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("expected error to contain %q, got:\n%s", want, msg)
+			}
+		}
+	})
+}
+
+func TestNewChecked(t *testing.T) {
+	t.Run("returns policy when valid", func(t *testing.T) {
+		p, err := retry.NewChecked(retry.WithMaxAttempts(5))
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if p == nil {
+			t.Fatal("expected policy")
+		}
+	})
+
+	t.Run("returns error when invalid", func(t *testing.T) {
+		p, err := retry.NewChecked(retry.WithMaxAttempts(-1))
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if p != nil {
+			t.Fatal("expected nil policy")
+		}
+	})
+}